@@ -0,0 +1,43 @@
+package character
+
+// HairStyleOf returns the hair style of a hair ID, regardless of color. Hair IDs encode the color in their last digit,
+// e.g. 30037 is style 30030 in color 7.
+func HairStyleOf(hair uint32) uint32 {
+	return hair - hair%10
+}
+
+// HairColorOf returns the color of a hair ID
+func HairColorOf(hair uint32) uint32 {
+	return hair % 10
+}
+
+// FaceStyleOf returns the face style of a face ID, regardless of lens color. Face IDs encode the lens color in their
+// hundreds digit, e.g. 20401 is style 20001 with lens color 4.
+func FaceStyleOf(face uint32) uint32 {
+	return face - FaceColorOf(face)*100
+}
+
+// FaceColorOf returns the lens color of a face ID
+func FaceColorOf(face uint32) uint32 {
+	return face / 100 % 10
+}
+
+// HairStyle returns the hair style of the character, regardless of color
+func (m Model) HairStyle() uint32 {
+	return HairStyleOf(m.hair)
+}
+
+// HairColor returns the hair color of the character
+func (m Model) HairColor() uint32 {
+	return HairColorOf(m.hair)
+}
+
+// FaceStyle returns the face style of the character, regardless of lens color
+func (m Model) FaceStyle() uint32 {
+	return FaceStyleOf(m.face)
+}
+
+// FaceColor returns the lens color of the character's face
+func (m Model) FaceColor() uint32 {
+	return FaceColorOf(m.face)
+}
@@ -0,0 +1,38 @@
+package character
+
+import "testing"
+
+func TestAppearance(t *testing.T) {
+	tests := []struct {
+		name          string
+		hair          uint32
+		face          uint32
+		wantHairStyle uint32
+		wantHairColor uint32
+		wantFaceStyle uint32
+		wantFaceColor uint32
+	}{
+		{name: "default colors", hair: 30030, face: 20000, wantHairStyle: 30030, wantHairColor: 0, wantFaceStyle: 20000, wantFaceColor: 0},
+		{name: "dyed hair", hair: 30037, face: 20001, wantHairStyle: 30030, wantHairColor: 7, wantFaceStyle: 20001, wantFaceColor: 0},
+		{name: "cosmetic lens", hair: 31002, face: 21401, wantHairStyle: 31000, wantHairColor: 2, wantFaceStyle: 21001, wantFaceColor: 4},
+		{name: "female face", hair: 34110, face: 21712, wantHairStyle: 34110, wantHairColor: 0, wantFaceStyle: 21012, wantFaceColor: 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewModelBuilder().SetHair(tt.hair).SetFace(tt.face).Build()
+			if got := m.HairStyle(); got != tt.wantHairStyle {
+				t.Errorf("HairStyle() = %d, want %d", got, tt.wantHairStyle)
+			}
+			if got := m.HairColor(); got != tt.wantHairColor {
+				t.Errorf("HairColor() = %d, want %d", got, tt.wantHairColor)
+			}
+			if got := m.FaceStyle(); got != tt.wantFaceStyle {
+				t.Errorf("FaceStyle() = %d, want %d", got, tt.wantFaceStyle)
+			}
+			if got := m.FaceColor(); got != tt.wantFaceColor {
+				t.Errorf("FaceColor() = %d, want %d", got, tt.wantFaceColor)
+			}
+		})
+	}
+}
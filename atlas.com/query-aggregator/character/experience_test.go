@@ -0,0 +1,87 @@
+package character
+
+import "testing"
+
+func TestExperienceForLevel(t *testing.T) {
+	tests := []struct {
+		name   string
+		level  byte
+		want   uint32
+		wantOk bool
+	}{
+		{name: "level 0", level: 0},
+		{name: "level 1", level: 1, want: 15, wantOk: true},
+		{name: "level 10", level: 10, want: 1716, wantOk: true},
+		{name: "level 199", level: 199, want: 2011069705, wantOk: true},
+		{name: "maximum level", level: 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ExperienceForLevel(tt.level)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("ExperienceForLevel(%d) = %d, %v, want %d, %v", tt.level, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestModel_Percentages(t *testing.T) {
+	tests := []struct {
+		name           string
+		model          Model
+		wantExperience int
+		wantHp         int
+		wantMp         int
+	}{
+		{
+			name:           "partial",
+			model:          NewModelBuilder().SetLevel(10).SetExperience(858).SetHp(499).SetMaxHp(1000).SetMp(50).SetMaxMp(50).Build(),
+			wantExperience: 50,
+			wantHp:         49,
+			wantMp:         100,
+		},
+		{
+			name:  "maximum level and no maximum HP or MP",
+			model: NewModelBuilder().SetLevel(200).SetExperience(1000).Build(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.model.ExperiencePercent(); got != tt.wantExperience {
+				t.Errorf("ExperiencePercent() = %d, want %d", got, tt.wantExperience)
+			}
+			if got := tt.model.HpPercent(); got != tt.wantHp {
+				t.Errorf("HpPercent() = %d, want %d", got, tt.wantHp)
+			}
+			if got := tt.model.MpPercent(); got != tt.wantMp {
+				t.Errorf("MpPercent() = %d, want %d", got, tt.wantMp)
+			}
+		})
+	}
+}
+
+func TestModel_RemainingSp(t *testing.T) {
+	tests := []struct {
+		name  string
+		jobId uint16
+		sp    string
+		want  uint16
+	}{
+		{name: "single skill book", jobId: 110, sp: "7", want: 7},
+		{name: "evan stage 1 uses the first book", jobId: 2200, sp: "3,0,0,0,0,0,0,0,0,0", want: 3},
+		{name: "evan stage 3 uses its own book", jobId: 2211, sp: "0,1,2,3,4,5,6,7,8,9", want: 2},
+		{name: "missing skill book", jobId: 2218, sp: "4", want: 0},
+		{name: "empty SP table", jobId: 100, sp: "", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewModelBuilder().SetJobId(tt.jobId).SetSp(tt.sp).Build()
+			if got := m.RemainingSp(); got != tt.want {
+				t.Errorf("RemainingSp() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package character
+
+import "github.com/Chronicle20/atlas-constants/job"
+
+// JobAdvancement is the advancement tier of a job
+type JobAdvancement int
+
+const (
+	BeginnerAdvancement JobAdvancement = 0
+	FirstAdvancement    JobAdvancement = 1
+	SecondAdvancement   JobAdvancement = 2
+	ThirdAdvancement    JobAdvancement = 3
+	FourthAdvancement   JobAdvancement = 4
+)
+
+// JobFamily is the group of jobs sharing a beginner job
+type JobFamily int
+
+const (
+	ExplorerFamily JobFamily = 0
+	CygnusFamily   JobFamily = 1
+	AranFamily     JobFamily = 2
+	EvanFamily     JobFamily = 3
+)
+
+// evanStages lists the Evan jobs in advancement order, following the Evan beginner job
+var evanStages = []job.Id{
+	job.EvanStage1Id, job.EvanStage2Id, job.EvanStage3Id, job.EvanStage4Id, job.EvanStage5Id,
+	job.EvanStage6Id, job.EvanStage7Id, job.EvanStage8Id, job.EvanStage9Id, job.EvanStage10Id,
+}
+
+// evanAdvancements maps each Evan stage to the tier reached at the same level by other jobs: stages 1 and 2 (levels
+// 10 and 20) to the first, 3 to 6 (levels 30 to 60) to the second, 7 and 8 (levels 80 and 100) to the third, and 9
+// and 10 (levels 120 and 160) to the fourth.
+var evanAdvancements = []JobAdvancement{
+	FirstAdvancement, FirstAdvancement,
+	SecondAdvancement, SecondAdvancement, SecondAdvancement, SecondAdvancement,
+	ThirdAdvancement, ThirdAdvancement,
+	FourthAdvancement, FourthAdvancement,
+}
+
+// evanStage returns the position of the job among the Evan stages
+func evanStage(id job.Id) (int, bool) {
+	for i, s := range evanStages {
+		if s == id {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// AdvancementOf returns the advancement tier of a job. Job IDs encode the tier in their digits, e.g. 0 (beginner),
+// 100 (first), 110 (second), 111 (third) and 112 (fourth), except for the Evan stages.
+func AdvancementOf(id job.Id) JobAdvancement {
+	if id == job.EvanId {
+		return BeginnerAdvancement
+	}
+	if stage, ok := evanStage(id); ok {
+		return evanAdvancements[stage]
+	}
+	switch {
+	case id%1000 == 0:
+		return BeginnerAdvancement
+	case id%100 == 0:
+		return FirstAdvancement
+	case id%10 == 0:
+		return SecondAdvancement
+	}
+	return SecondAdvancement + JobAdvancement(id%10)
+}
+
+// FamilyOf returns the family of a job
+func FamilyOf(id job.Id) JobFamily {
+	if id == job.EvanId {
+		return EvanFamily
+	}
+	if _, ok := evanStage(id); ok {
+		return EvanFamily
+	}
+	switch id / 1000 {
+	case 1:
+		return CygnusFamily
+	case 2:
+		return AranFamily
+	}
+	return ExplorerFamily
+}
+
+// ParentOf returns the job advanced from to reach the job, or false for beginner jobs
+func ParentOf(id job.Id) (job.Id, bool) {
+	if stage, ok := evanStage(id); ok {
+		if stage == 0 {
+			return job.EvanId, true
+		}
+		return evanStages[stage-1], true
+	}
+	switch AdvancementOf(id) {
+	case BeginnerAdvancement:
+		return 0, false
+	case FirstAdvancement:
+		return id - id%1000, true
+	case SecondAdvancement:
+		return id - id%100, true
+	}
+	return id - 1, true
+}
+
+// DescendsFrom returns whether the job is the ancestor job or was reached by advancing from it
+func DescendsFrom(id job.Id, ancestor job.Id) bool {
+	for {
+		if id == ancestor {
+			return true
+		}
+		parent, ok := ParentOf(id)
+		if !ok {
+			return false
+		}
+		id = parent
+	}
+}
+
+// JobAdvancement returns the advancement tier of the character's job
+func (m Model) JobAdvancement() JobAdvancement {
+	return AdvancementOf(job.Id(m.jobId))
+}
+
+// JobFamily returns the family of the character's job
+func (m Model) JobFamily() JobFamily {
+	return FamilyOf(job.Id(m.jobId))
+}
+
+// JobDescendsFrom returns whether the character's job is the ancestor job or was reached by advancing from it
+func (m Model) JobDescendsFrom(ancestor job.Id) bool {
+	return DescendsFrom(job.Id(m.jobId), ancestor)
+}
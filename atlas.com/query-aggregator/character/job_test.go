@@ -0,0 +1,79 @@
+package character
+
+import (
+	"testing"
+
+	"github.com/Chronicle20/atlas-constants/job"
+)
+
+func TestJobLineage(t *testing.T) {
+	tests := []struct {
+		name            string
+		id              job.Id
+		wantAdvancement JobAdvancement
+		wantFamily      JobFamily
+		wantParent      job.Id
+		wantHasParent   bool
+	}{
+		{name: "beginner", id: 0, wantAdvancement: BeginnerAdvancement, wantFamily: ExplorerFamily},
+		{name: "warrior", id: 100, wantAdvancement: FirstAdvancement, wantFamily: ExplorerFamily, wantParent: 0, wantHasParent: true},
+		{name: "fighter", id: 110, wantAdvancement: SecondAdvancement, wantFamily: ExplorerFamily, wantParent: 100, wantHasParent: true},
+		{name: "crusader", id: 111, wantAdvancement: ThirdAdvancement, wantFamily: ExplorerFamily, wantParent: 110, wantHasParent: true},
+		{name: "hero", id: 112, wantAdvancement: FourthAdvancement, wantFamily: ExplorerFamily, wantParent: 111, wantHasParent: true},
+		{name: "priest", id: 231, wantAdvancement: ThirdAdvancement, wantFamily: ExplorerFamily, wantParent: 230, wantHasParent: true},
+		{name: "noblesse", id: 1000, wantAdvancement: BeginnerAdvancement, wantFamily: CygnusFamily},
+		{name: "dawn warrior 2", id: 1110, wantAdvancement: SecondAdvancement, wantFamily: CygnusFamily, wantParent: 1100, wantHasParent: true},
+		{name: "legend", id: 2000, wantAdvancement: BeginnerAdvancement, wantFamily: AranFamily},
+		{name: "aran 1", id: 2100, wantAdvancement: FirstAdvancement, wantFamily: AranFamily, wantParent: 2000, wantHasParent: true},
+		{name: "aran 4", id: 2112, wantAdvancement: FourthAdvancement, wantFamily: AranFamily, wantParent: 2111, wantHasParent: true},
+		{name: "evan beginner", id: job.EvanId, wantAdvancement: BeginnerAdvancement, wantFamily: EvanFamily},
+		{name: "evan 1", id: job.EvanStage1Id, wantAdvancement: FirstAdvancement, wantFamily: EvanFamily, wantParent: job.EvanId, wantHasParent: true},
+		{name: "evan 2", id: job.EvanStage2Id, wantAdvancement: FirstAdvancement, wantFamily: EvanFamily, wantParent: job.EvanStage1Id, wantHasParent: true},
+		{name: "evan 3", id: job.EvanStage3Id, wantAdvancement: SecondAdvancement, wantFamily: EvanFamily, wantParent: job.EvanStage2Id, wantHasParent: true},
+		{name: "evan 7", id: job.EvanStage7Id, wantAdvancement: ThirdAdvancement, wantFamily: EvanFamily, wantParent: job.EvanStage6Id, wantHasParent: true},
+		{name: "evan 10", id: job.EvanStage10Id, wantAdvancement: FourthAdvancement, wantFamily: EvanFamily, wantParent: job.EvanStage9Id, wantHasParent: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AdvancementOf(tt.id); got != tt.wantAdvancement {
+				t.Errorf("AdvancementOf(%d) = %d, want %d", tt.id, got, tt.wantAdvancement)
+			}
+			if got := FamilyOf(tt.id); got != tt.wantFamily {
+				t.Errorf("FamilyOf(%d) = %d, want %d", tt.id, got, tt.wantFamily)
+			}
+			parent, ok := ParentOf(tt.id)
+			if ok != tt.wantHasParent || parent != tt.wantParent {
+				t.Errorf("ParentOf(%d) = %d, %v, want %d, %v", tt.id, parent, ok, tt.wantParent, tt.wantHasParent)
+			}
+		})
+	}
+}
+
+func TestDescendsFrom(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       job.Id
+		ancestor job.Id
+		want     bool
+	}{
+		{name: "same job", id: 110, ancestor: 110, want: true},
+		{name: "fourth job from first", id: 112, ancestor: 100, want: true},
+		{name: "fourth job from beginner", id: 112, ancestor: 0, want: true},
+		{name: "sibling branch", id: 122, ancestor: 110, want: false},
+		{name: "ancestor does not descend", id: 100, ancestor: 110, want: false},
+		{name: "other family", id: 1112, ancestor: 100, want: false},
+		{name: "aran from legend", id: 2111, ancestor: 2000, want: true},
+		{name: "evan from evan beginner", id: job.EvanStage10Id, ancestor: job.EvanId, want: true},
+		{name: "evan from earlier stage", id: job.EvanStage5Id, ancestor: job.EvanStage2Id, want: true},
+		{name: "evan not from aran", id: job.EvanStage5Id, ancestor: 2000, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DescendsFrom(tt.id, tt.ancestor); got != tt.want {
+				t.Errorf("DescendsFrom(%d, %d) = %v, want %v", tt.id, tt.ancestor, got, tt.want)
+			}
+		})
+	}
+}
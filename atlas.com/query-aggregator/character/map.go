@@ -0,0 +1,24 @@
+package character
+
+// RegionOf returns the region of a map. Map IDs group the maps of an area by their leading three digits, e.g.
+// 220000000 through 220999999 are Ludibrium (region 220) and 910000000 through 910999999 the Free Market (region 910).
+func RegionOf(mapId uint32) uint32 {
+	return mapId / 1000000
+}
+
+// ContinentOf returns the continent of a map. Map IDs group the areas of a continent by their leading digit, the
+// hundred-millions digit of the nine-digit ID, e.g. 0 is Maple Island, 1 Victoria Island and 2 Ossyria, which holds
+// Orbis (200), El Nath (211) and Ludibrium (220).
+func ContinentOf(mapId uint32) uint32 {
+	return mapId / 100000000
+}
+
+// MapRegion returns the region of the map the character is in
+func (m Model) MapRegion() uint32 {
+	return RegionOf(m.mapId)
+}
+
+// MapContinent returns the continent of the map the character is in
+func (m Model) MapContinent() uint32 {
+	return ContinentOf(m.mapId)
+}
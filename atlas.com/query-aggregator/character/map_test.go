@@ -0,0 +1,30 @@
+package character
+
+import "testing"
+
+func TestMapRegionAndContinent(t *testing.T) {
+	tests := []struct {
+		name          string
+		mapId         uint32
+		wantRegion    uint32
+		wantContinent uint32
+	}{
+		{name: "maple island", mapId: 10000, wantRegion: 0, wantContinent: 0},
+		{name: "henesys", mapId: 100000000, wantRegion: 100, wantContinent: 1},
+		{name: "el nath", mapId: 211000000, wantRegion: 211, wantContinent: 2},
+		{name: "ludibrium", mapId: 220000300, wantRegion: 220, wantContinent: 2},
+		{name: "free market", mapId: 910000002, wantRegion: 910, wantContinent: 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewModelBuilder().SetMapId(tt.mapId).Build()
+			if got := m.MapRegion(); got != tt.wantRegion {
+				t.Errorf("MapRegion() = %d, want %d", got, tt.wantRegion)
+			}
+			if got := m.MapContinent(); got != tt.wantContinent {
+				t.Errorf("MapContinent() = %d, want %d", got, tt.wantContinent)
+			}
+		})
+	}
+}
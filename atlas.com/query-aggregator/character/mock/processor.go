@@ -2,14 +2,18 @@ package mock
 
 import (
 	"atlas-query-aggregator/character"
+	"github.com/Chronicle20/atlas-constants/world"
 	"github.com/Chronicle20/atlas-model/model"
 )
 
 // ProcessorImpl is a mock implementation of the character.ProcessorImpl
 type ProcessorImpl struct {
-	GetByIdFunc            func(decorators ...model.Decorator[character.Model]) func(characterId uint32) (character.Model, error)
-	InventoryDecoratorFunc func(m character.Model) character.Model
-	GuildDecoratorFunc     func(m character.Model) character.Model
+	GetByIdFunc              func(decorators ...model.Decorator[character.Model]) func(characterId uint32) (character.Model, error)
+	GetByAccountAndWorldFunc func(decorators ...model.Decorator[character.Model]) func(accountId uint32, worldId world.Id) ([]character.Model, error)
+	InventoryDecoratorFunc   func(m character.Model) character.Model
+	GuildDecoratorFunc       func(m character.Model) character.Model
+	WithInventoryFunc        func(m character.Model) (character.Model, error)
+	WithGuildFunc            func(m character.Model) (character.Model, error)
 }
 
 // GetById returns a function that gets a character by ID
@@ -22,6 +26,16 @@ func (m *ProcessorImpl) GetById(decorators ...model.Decorator[character.Model])
 	}
 }
 
+// GetByAccountAndWorld returns a function that gets the characters an account has in a world
+func (m *ProcessorImpl) GetByAccountAndWorld(decorators ...model.Decorator[character.Model]) func(accountId uint32, worldId world.Id) ([]character.Model, error) {
+	if m.GetByAccountAndWorldFunc != nil {
+		return m.GetByAccountAndWorldFunc(decorators...)
+	}
+	return func(accountId uint32, worldId world.Id) ([]character.Model, error) {
+		return []character.Model{}, nil
+	}
+}
+
 func (m *ProcessorImpl) InventoryDecorator(mo character.Model) character.Model {
 	if m.InventoryDecoratorFunc != nil {
 		return m.InventoryDecoratorFunc(mo)
@@ -35,3 +49,19 @@ func (m *ProcessorImpl) GuildDecorator(mo character.Model) character.Model {
 	}
 	return mo
 }
+
+// WithInventory defaults to the InventoryDecorator behavior when no function is provided
+func (m *ProcessorImpl) WithInventory(mo character.Model) (character.Model, error) {
+	if m.WithInventoryFunc != nil {
+		return m.WithInventoryFunc(mo)
+	}
+	return m.InventoryDecorator(mo), nil
+}
+
+// WithGuild defaults to the GuildDecorator behavior when no function is provided
+func (m *ProcessorImpl) WithGuild(mo character.Model) (character.Model, error) {
+	if m.WithGuildFunc != nil {
+		return m.WithGuildFunc(mo)
+	}
+	return m.GuildDecorator(mo), nil
+}
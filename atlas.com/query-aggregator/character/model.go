@@ -192,8 +192,14 @@ func (m Model) Sp() []uint16 {
 	return sps
 }
 
+// RemainingSp returns the unspent SP of the skill book of the character's job, or 0 when the SP table has no entry for it
 func (m Model) RemainingSp() uint16 {
-	return m.Sp()[m.skillBook()]
+	sps := m.Sp()
+	book := int(m.skillBook())
+	if book >= len(sps) {
+		return 0
+	}
+	return sps[book]
 }
 
 func (m Model) skillBook() uint16 {
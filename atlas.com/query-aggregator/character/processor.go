@@ -4,6 +4,7 @@ import (
 	"atlas-query-aggregator/guild"
 	"atlas-query-aggregator/inventory"
 	"context"
+	"github.com/Chronicle20/atlas-constants/world"
 	"github.com/Chronicle20/atlas-model/model"
 	"github.com/Chronicle20/atlas-rest/requests"
 	"github.com/sirupsen/logrus"
@@ -11,8 +12,11 @@ import (
 
 type Processor interface {
 	GetById(decorators ...model.Decorator[Model]) func(characterId uint32) (Model, error)
+	GetByAccountAndWorld(decorators ...model.Decorator[Model]) func(accountId uint32, worldId world.Id) ([]Model, error)
 	InventoryDecorator(m Model) Model
 	GuildDecorator(m Model) Model
+	WithInventory(m Model) (Model, error)
+	WithGuild(m Model) (Model, error)
 }
 
 type ProcessorImpl struct {
@@ -39,18 +43,38 @@ func (p *ProcessorImpl) GetById(decorators ...model.Decorator[Model]) func(chara
 	}
 }
 
+// GetByAccountAndWorld retrieves every character the account has in the world
+func (p *ProcessorImpl) GetByAccountAndWorld(decorators ...model.Decorator[Model]) func(accountId uint32, worldId world.Id) ([]Model, error) {
+	return func(accountId uint32, worldId world.Id) ([]Model, error) {
+		mp := requests.SliceProvider[RestModel, Model](p.l, p.ctx)(requestByAccountAndWorld(accountId, worldId), Extract, model.Filters[Model]())
+		return model.SliceMap(model.Decorate(decorators))(mp)()()
+	}
+}
+
 func (p *ProcessorImpl) InventoryDecorator(m Model) Model {
+	r, _ := p.WithInventory(m)
+	return r
+}
+
+func (p *ProcessorImpl) GuildDecorator(m Model) Model {
+	r, _ := p.WithGuild(m)
+	return r
+}
+
+// WithInventory attaches the character's inventory, returning the undecorated model and the error when it cannot be retrieved
+func (p *ProcessorImpl) WithInventory(m Model) (Model, error) {
 	i, err := p.ip.GetByCharacterId(m.Id())
 	if err != nil {
-		return m
+		return m, err
 	}
-	return m.SetInventory(i)
+	return m.SetInventory(i), nil
 }
 
-func (p *ProcessorImpl) GuildDecorator(m Model) Model {
+// WithGuild attaches the character's guild, returning the undecorated model and the error when it cannot be retrieved
+func (p *ProcessorImpl) WithGuild(m Model) (Model, error) {
 	g, err := p.gp.GetByMemberId()(m.Id())
 	if err != nil {
-		return m
+		return m, err
 	}
-	return m.SetGuild(g)
+	return m.SetGuild(g), nil
 }
@@ -3,12 +3,14 @@ package character
 import (
 	"atlas-query-aggregator/rest"
 	"fmt"
+	"github.com/Chronicle20/atlas-constants/world"
 	"github.com/Chronicle20/atlas-rest/requests"
 )
 
 const (
-	Resource = "characters"
-	ById     = Resource + "/%d"
+	Resource          = "characters"
+	ById              = Resource + "/%d"
+	ByAccountAndWorld = Resource + "?accountId=%d&worldId=%d"
 )
 
 func getBaseRequest() string {
@@ -18,3 +20,7 @@ func getBaseRequest() string {
 func requestById(id uint32) requests.Request[RestModel] {
 	return rest.MakeGetRequest[RestModel](fmt.Sprintf(getBaseRequest()+ById, id))
 }
+
+func requestByAccountAndWorld(accountId uint32, worldId world.Id) requests.Request[[]RestModel] {
+	return rest.MakeGetRequest[[]RestModel](fmt.Sprintf(getBaseRequest()+ByAccountAndWorld, accountId, worldId))
+}
@@ -30,6 +30,21 @@ func (m Model) Assets() []asset.Model[any] {
 	return m.assets
 }
 
+// FreeSlots returns the number of unoccupied slots. Equipped items, which occupy negative slots, do not take up room.
+func (m Model) FreeSlots() int {
+	occupied := make(map[int16]bool)
+	for _, a := range m.assets {
+		if a.Slot() > 0 {
+			occupied[a.Slot()] = true
+		}
+	}
+	free := int(m.capacity) - len(occupied)
+	if free < 0 {
+		return 0
+	}
+	return free
+}
+
 func (m Model) CharacterId() uint32 {
 	return m.characterId
 }
@@ -0,0 +1,73 @@
+package conditionset
+
+import (
+	"atlas-query-aggregator/database"
+	"atlas-query-aggregator/validation"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.etcd.io/bbolt"
+)
+
+func put(b *bbolt.Bucket, e entity) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return b.Put([]byte(e.Id.String()), data)
+}
+
+func create(db *bbolt.DB, tenantId uuid.UUID, name string, description string, conditions []validation.ConditionInput) (entity, error) {
+	e := entity{
+		Id: uuid.New(),
+		Versions: []versionEntity{{
+			Version:     1,
+			Name:        name,
+			Description: description,
+			Conditions:  conditions,
+			CreatedAt:   time.Now(),
+		}},
+	}
+	err := database.UpdateTenant(db, collection, tenantId, func(b *bbolt.Bucket) error {
+		return put(b, e)
+	})
+	return e, err
+}
+
+// update appends a new version, derived from the latest version by next, to the condition set. The latest version is
+// read and the new one written in one transaction, so concurrent updates each build on the other. Earlier versions are
+// retained so pinned callers are unaffected.
+func update(db *bbolt.DB, tenantId uuid.UUID, id uuid.UUID, next func(latest versionEntity) (versionEntity, error)) (entity, error) {
+	var e entity
+	err := database.UpdateTenant(db, collection, tenantId, func(b *bbolt.Bucket) error {
+		data := b.Get([]byte(id.String()))
+		if data == nil {
+			return fmt.Errorf("condition set %s: %w", id, ErrNotFound)
+		}
+		if err := json.Unmarshal(data, &e); err != nil {
+			return err
+		}
+
+		latest := e.latest()
+		v, err := next(latest)
+		if err != nil {
+			return err
+		}
+		v.Version = latest.Version + 1
+		v.CreatedAt = time.Now()
+		e.Versions = append(e.Versions, v)
+		return put(b, e)
+	})
+	return e, err
+}
+
+func remove(db *bbolt.DB, tenantId uuid.UUID, id uuid.UUID) error {
+	return database.UpdateTenant(db, collection, tenantId, func(b *bbolt.Bucket) error {
+		if b.Get([]byte(id.String())) == nil {
+			return fmt.Errorf("condition set %s: %w", id, ErrNotFound)
+		}
+		return b.Delete([]byte(id.String()))
+	})
+}
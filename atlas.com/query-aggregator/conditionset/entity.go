@@ -0,0 +1,58 @@
+package conditionset
+
+import (
+	"atlas-query-aggregator/validation"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const collection = "condition-sets"
+
+// entity is the persisted form of a condition set, holding every version ever written
+type entity struct {
+	Id       uuid.UUID       `json:"id"`
+	Versions []versionEntity `json:"versions"`
+}
+
+type versionEntity struct {
+	Version     uint32                      `json:"version"`
+	Name        string                      `json:"name"`
+	Description string                      `json:"description"`
+	Conditions  []validation.ConditionInput `json:"conditions"`
+	CreatedAt   time.Time                   `json:"createdAt"`
+}
+
+// latest returns the most recent version of the entity
+func (e entity) latest() versionEntity {
+	return e.Versions[len(e.Versions)-1]
+}
+
+// Make converts a version of the entity into a model. A version of 0 selects the latest version.
+func Make(e entity, version uint32) (Model, error) {
+	v := e.latest()
+	if version != 0 {
+		found := false
+		for _, ve := range e.Versions {
+			if ve.Version == version {
+				v = ve
+				found = true
+				break
+			}
+		}
+		if !found {
+			return Model{}, fmt.Errorf("version %d of condition set %s: %w", version, e.Id, ErrNotFound)
+		}
+	}
+
+	return Model{
+		id:            e.Id,
+		name:          v.Name,
+		description:   v.Description,
+		version:       v.Version,
+		latestVersion: e.latest().Version,
+		conditions:    v.Conditions,
+		createdAt:     v.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,54 @@
+package conditionset
+
+import (
+	"atlas-query-aggregator/validation"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Model represents one version of a named condition set
+type Model struct {
+	id            uuid.UUID
+	name          string
+	description   string
+	version       uint32
+	latestVersion uint32
+	conditions    []validation.ConditionInput
+	createdAt     time.Time
+}
+
+// Id returns the condition set ID, shared by all of its versions
+func (m Model) Id() uuid.UUID {
+	return m.id
+}
+
+// Name returns the condition set name
+func (m Model) Name() string {
+	return m.name
+}
+
+// Description returns the condition set description
+func (m Model) Description() string {
+	return m.description
+}
+
+// Version returns the version this model represents
+func (m Model) Version() uint32 {
+	return m.version
+}
+
+// LatestVersion returns the most recent version of the condition set
+func (m Model) LatestVersion() uint32 {
+	return m.latestVersion
+}
+
+// Conditions returns the conditions of this version
+func (m Model) Conditions() []validation.ConditionInput {
+	return m.conditions
+}
+
+// CreatedAt returns when this version was created
+func (m Model) CreatedAt() time.Time {
+	return m.createdAt
+}
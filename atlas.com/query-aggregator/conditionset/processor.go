@@ -0,0 +1,173 @@
+package conditionset
+
+import (
+	"atlas-query-aggregator/validation"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/Chronicle20/atlas-model/model"
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"go.etcd.io/bbolt"
+)
+
+var (
+	// ErrNotFound is returned when a condition set or version does not exist for the tenant
+	ErrNotFound = errors.New("condition set not found")
+
+	// ErrInvalid is returned when a condition set fails validation
+	ErrInvalid = errors.New("invalid condition set")
+)
+
+// Processor defines the interface for condition set operations
+type Processor interface {
+	// ByIdProvider provides a version of a condition set. A version of 0 provides the latest version.
+	ByIdProvider(id uuid.UUID, version uint32) model.Provider[Model]
+
+	// GetById retrieves a version of a condition set. A version of 0 retrieves the latest version.
+	GetById(id uuid.UUID, version uint32) (Model, error)
+
+	// GetAll retrieves the latest version of every condition set for the tenant
+	GetAll() ([]Model, error)
+
+	// Create stores a new condition set as version 1
+	Create(name string, description string, conditions []validation.ConditionInput) (Model, error)
+
+	// Update stores a new version of an existing condition set. An empty name or description, or nil conditions, carry
+	// over from the latest version.
+	Update(id uuid.UUID, name string, description string, conditions []validation.ConditionInput) (Model, error)
+
+	// Delete removes a condition set and all of its versions
+	Delete(id uuid.UUID) error
+}
+
+// ProcessorImpl implements the Processor interface
+type ProcessorImpl struct {
+	l   logrus.FieldLogger
+	ctx context.Context
+	db  *bbolt.DB
+	t   tenant.Model
+}
+
+// NewProcessor creates a new condition set processor
+func NewProcessor(l logrus.FieldLogger, ctx context.Context, db *bbolt.DB) Processor {
+	return &ProcessorImpl{
+		l:   l,
+		ctx: ctx,
+		db:  db,
+		t:   tenant.MustFromContext(ctx),
+	}
+}
+
+// ByIdProvider provides a version of a condition set
+func (p *ProcessorImpl) ByIdProvider(id uuid.UUID, version uint32) model.Provider[Model] {
+	return func() (Model, error) {
+		e, err := getById(p.db, p.t.Id(), id)
+		if err != nil {
+			return Model{}, err
+		}
+		return Make(e, version)
+	}
+}
+
+// GetById retrieves a version of a condition set
+func (p *ProcessorImpl) GetById(id uuid.UUID, version uint32) (Model, error) {
+	return p.ByIdProvider(id, version)()
+}
+
+// GetAll retrieves the latest version of every condition set, ordered by name
+func (p *ProcessorImpl) GetAll() ([]Model, error) {
+	es, err := getAll(p.db, p.t.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Model, 0, len(es))
+	for _, e := range es {
+		m, err := Make(e, 0)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, m)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name() < results[j].Name()
+	})
+	return results, nil
+}
+
+// Create stores a new condition set as version 1
+func (p *ProcessorImpl) Create(name string, description string, conditions []validation.ConditionInput) (Model, error) {
+	if err := validateSet(name, conditions); err != nil {
+		return Model{}, err
+	}
+
+	e, err := create(p.db, p.t.Id(), name, description, conditions)
+	if err != nil {
+		return Model{}, err
+	}
+	p.l.Debugf("Created condition set [%s] named [%s].", e.Id, name)
+	return Make(e, 0)
+}
+
+// Update stores a new version of an existing condition set, merging the given attributes into the latest version
+func (p *ProcessorImpl) Update(id uuid.UUID, name string, description string, conditions []validation.ConditionInput) (Model, error) {
+	e, err := update(p.db, p.t.Id(), id, func(latest versionEntity) (versionEntity, error) {
+		v := versionEntity{Name: latest.Name, Description: latest.Description, Conditions: latest.Conditions}
+		if name != "" {
+			v.Name = name
+		}
+		if description != "" {
+			v.Description = description
+		}
+		if conditions != nil {
+			v.Conditions = conditions
+		}
+		return v, validateSet(v.Name, v.Conditions)
+	})
+	if err != nil {
+		return Model{}, err
+	}
+	p.l.Debugf("Updated condition set [%s] to version [%d].", e.Id, e.latest().Version)
+	return Make(e, 0)
+}
+
+// Delete removes a condition set and all of its versions
+func (p *ProcessorImpl) Delete(id uuid.UUID) error {
+	err := remove(p.db, p.t.Id(), id)
+	if err != nil {
+		return err
+	}
+	p.l.Debugf("Deleted condition set [%s].", id)
+	return nil
+}
+
+// validateSet checks that a condition set is named and holds valid conditions
+func validateSet(name string, conditions []validation.ConditionInput) error {
+	if name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalid)
+	}
+	if err := validation.ValidateConditions(conditions); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+	return nil
+}
+
+// Resolver gives validation requests access to the stored condition sets of the requesting tenant
+func Resolver(db *bbolt.DB) validation.ConditionSetResolverProvider {
+	return func(l logrus.FieldLogger, ctx context.Context) validation.ConditionSetResolver {
+		return func(id uuid.UUID, version uint32) ([]validation.ConditionInput, uint32, error) {
+			m, err := NewProcessor(l, ctx, db).GetById(id, version)
+			if errors.Is(err, ErrNotFound) {
+				return nil, 0, fmt.Errorf("%w: %w", validation.ErrConditionSetNotFound, err)
+			}
+			if err != nil {
+				return nil, 0, err
+			}
+			return m.Conditions(), m.Version(), nil
+		}
+	}
+}
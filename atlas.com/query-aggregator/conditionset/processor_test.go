@@ -0,0 +1,178 @@
+package conditionset
+
+import (
+	"atlas-query-aggregator/validation"
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"go.etcd.io/bbolt"
+)
+
+func setupTest(t *testing.T) (*bbolt.DB, context.Context) {
+	db, err := bbolt.Open(filepath.Join(t.TempDir(), "test.db"), 0600, nil)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	tm, err := tenant.Create(uuid.New(), "GMS", 83, 1)
+	if err != nil {
+		t.Fatalf("Failed to create tenant: %v", err)
+	}
+	return db, tenant.WithContext(context.Background(), tm)
+}
+
+func levelConditions(value int) []validation.ConditionInput {
+	return []validation.ConditionInput{{Type: "level", Operator: ">=", Value: value}}
+}
+
+func TestProcessor_Versioning(t *testing.T) {
+	db, ctx := setupTest(t)
+	p := NewProcessor(logrus.New(), ctx, db)
+
+	created, err := p.Create("Zakum entry", "", levelConditions(50))
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if created.Version() != 1 || created.LatestVersion() != 1 {
+		t.Errorf("Expected version 1, got version %d latest %d", created.Version(), created.LatestVersion())
+	}
+
+	updated, err := p.Update(created.Id(), "Zakum entry", "Raised requirement", levelConditions(70))
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if updated.Version() != 2 {
+		t.Errorf("Expected version 2, got %d", updated.Version())
+	}
+
+	latest, err := p.GetById(created.Id(), 0)
+	if err != nil {
+		t.Fatalf("GetById failed: %v", err)
+	}
+	if latest.Version() != 2 || latest.Conditions()[0].Value != 70 {
+		t.Errorf("Expected latest version 2 with value 70, got version %d with value %d", latest.Version(), latest.Conditions()[0].Value)
+	}
+
+	pinned, err := p.GetById(created.Id(), 1)
+	if err != nil {
+		t.Fatalf("GetById pinned failed: %v", err)
+	}
+	if pinned.Version() != 1 || pinned.LatestVersion() != 2 || pinned.Conditions()[0].Value != 50 {
+		t.Errorf("Expected pinned version 1 with value 50, got version %d with value %d", pinned.Version(), pinned.Conditions()[0].Value)
+	}
+
+	if _, err = p.GetById(created.Id(), 3); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for missing version, got %v", err)
+	}
+
+	if err = p.Delete(created.Id()); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err = p.GetById(created.Id(), 0); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after delete, got %v", err)
+	}
+}
+
+func TestProcessor_PartialUpdate(t *testing.T) {
+	db, ctx := setupTest(t)
+	p := NewProcessor(logrus.New(), ctx, db)
+
+	created, err := p.Create("Zakum entry", "Entry requirement", levelConditions(50))
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	// Concurrent updates of different attributes each build on the other, so no attribute is lost
+	updates := []func() (Model, error){
+		func() (Model, error) { return p.Update(created.Id(), "Zakum raid", "", nil) },
+		func() (Model, error) { return p.Update(created.Id(), "", "Raised requirement", nil) },
+		func() (Model, error) { return p.Update(created.Id(), "", "", levelConditions(70)) },
+	}
+	var wg sync.WaitGroup
+	errs := make([]error, len(updates))
+	for i, u := range updates {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = u()
+		}()
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("Update %d failed: %v", i, err)
+		}
+	}
+
+	latest, err := p.GetById(created.Id(), 0)
+	if err != nil {
+		t.Fatalf("GetById failed: %v", err)
+	}
+	if latest.Version() != 4 || latest.Name() != "Zakum raid" || latest.Description() != "Raised requirement" || latest.Conditions()[0].Value != 70 {
+		t.Errorf("Expected version 4 merging every update, got version %d %q %q with value %d", latest.Version(), latest.Name(), latest.Description(), latest.Conditions()[0].Value)
+	}
+
+	if _, err := p.Update(created.Id(), "", "", []validation.ConditionInput{{Type: "unknown", Operator: "=", Value: 1}}); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Expected ErrInvalid for invalid conditions, got %v", err)
+	}
+	if latest, _ = p.GetById(created.Id(), 0); latest.Version() != 4 {
+		t.Errorf("Expected a rejected update to store no version, got version %d", latest.Version())
+	}
+}
+
+func TestProcessor_Validation(t *testing.T) {
+	db, ctx := setupTest(t)
+	p := NewProcessor(logrus.New(), ctx, db)
+
+	tests := []struct {
+		name       string
+		setName    string
+		conditions []validation.ConditionInput
+	}{
+		{name: "Missing name", setName: "", conditions: levelConditions(10)},
+		{name: "Invalid condition", setName: "Broken", conditions: []validation.ConditionInput{{Type: "unknown", Operator: "=", Value: 1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := p.Create(tt.setName, "", tt.conditions); !errors.Is(err, ErrInvalid) {
+				t.Errorf("Expected ErrInvalid, got %v", err)
+			}
+		})
+	}
+
+	if _, err := p.Update(uuid.New(), "Missing", "", levelConditions(10)); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound updating unknown set, got %v", err)
+	}
+}
+
+func TestProcessor_TenantIsolation(t *testing.T) {
+	db, ctx := setupTest(t)
+	created, err := NewProcessor(logrus.New(), ctx, db).Create("Shared name", "", levelConditions(10))
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	other, err := tenant.Create(uuid.New(), "GMS", 83, 1)
+	if err != nil {
+		t.Fatalf("Failed to create tenant: %v", err)
+	}
+	op := NewProcessor(logrus.New(), tenant.WithContext(context.Background(), other), db)
+	if _, err = op.GetById(created.Id(), 0); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound from another tenant, got %v", err)
+	}
+	all, err := op.GetAll()
+	if err != nil {
+		t.Fatalf("GetAll failed: %v", err)
+	}
+	if len(all) != 0 {
+		t.Errorf("Expected no condition sets for another tenant, got %d", len(all))
+	}
+}
@@ -0,0 +1,43 @@
+package conditionset
+
+import (
+	"atlas-query-aggregator/database"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"go.etcd.io/bbolt"
+)
+
+func getById(db *bbolt.DB, tenantId uuid.UUID, id uuid.UUID) (entity, error) {
+	var result entity
+	err := database.ViewTenant(db, collection, tenantId, func(b *bbolt.Bucket) error {
+		if b == nil {
+			return fmt.Errorf("condition set %s: %w", id, ErrNotFound)
+		}
+		data := b.Get([]byte(id.String()))
+		if data == nil {
+			return fmt.Errorf("condition set %s: %w", id, ErrNotFound)
+		}
+		return json.Unmarshal(data, &result)
+	})
+	return result, err
+}
+
+func getAll(db *bbolt.DB, tenantId uuid.UUID) ([]entity, error) {
+	results := make([]entity, 0)
+	err := database.ViewTenant(db, collection, tenantId, func(b *bbolt.Bucket) error {
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, v []byte) error {
+			var e entity
+			if err := json.Unmarshal(v, &e); err != nil {
+				return err
+			}
+			results = append(results, e)
+			return nil
+		})
+	})
+	return results, err
+}
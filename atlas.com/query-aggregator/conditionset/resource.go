@@ -0,0 +1,150 @@
+package conditionset
+
+import (
+	"atlas-query-aggregator/rest"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/Chronicle20/atlas-rest/server"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/jtumidanski/api2go/jsonapi"
+	"github.com/sirupsen/logrus"
+	"go.etcd.io/bbolt"
+)
+
+// InitResource registers the routes with the router
+func InitResource(si jsonapi.ServerInformation) func(db *bbolt.DB) server.RouteInitializer {
+	return func(db *bbolt.DB) server.RouteInitializer {
+		return func(r *mux.Router, l logrus.FieldLogger) {
+			router := r.PathPrefix("/condition-sets").Subrouter()
+			router.HandleFunc("", rest.RegisterHandler(l)(si)("get_condition_sets", handleGetConditionSets(db))).Methods(http.MethodGet)
+			router.HandleFunc("", rest.RegisterInputHandler[RestModel](l)(si)("create_condition_set", handleCreateConditionSet(db))).Methods(http.MethodPost)
+			router.HandleFunc("/{conditionSetId}", rest.RegisterHandler(l)(si)("get_condition_set", handleGetConditionSet(db))).Methods(http.MethodGet)
+			router.HandleFunc("/{conditionSetId}", rest.RegisterInputHandler[RestModel](l)(si)("update_condition_set", handleUpdateConditionSet(db))).Methods(http.MethodPatch)
+			router.HandleFunc("/{conditionSetId}", rest.RegisterHandler(l)(si)("delete_condition_set", handleDeleteConditionSet(db))).Methods(http.MethodDelete)
+		}
+	}
+}
+
+func handleGetConditionSets(db *bbolt.DB) rest.GetHandler {
+	return func(d *rest.HandlerDependency, c *rest.HandlerContext) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			ms, err := NewProcessor(d.Logger(), d.Context(), db).GetAll()
+			if err != nil {
+				d.Logger().WithError(err).Errorf("Unable to retrieve condition sets.")
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			res, err := model.SliceMap(Transform)(model.FixedProvider(ms))()()
+			if err != nil {
+				d.Logger().WithError(err).Errorf("Creating REST model.")
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			query := r.URL.Query()
+			queryParams := jsonapi.ParseQueryFields(&query)
+			server.MarshalResponse[[]RestModel](d.Logger())(w)(c.ServerInformation())(queryParams)(res)
+		}
+	}
+}
+
+func handleGetConditionSet(db *bbolt.DB) rest.GetHandler {
+	return func(d *rest.HandlerDependency, c *rest.HandlerContext) http.HandlerFunc {
+		return rest.ParseConditionSetId(d.Logger(), func(conditionSetId uuid.UUID) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				var version uint64
+				if v := r.URL.Query().Get("version"); v != "" {
+					var err error
+					version, err = strconv.ParseUint(v, 10, 32)
+					if err != nil {
+						d.Logger().WithError(err).Errorf("Unable to properly parse version from query.")
+						w.WriteHeader(http.StatusBadRequest)
+						return
+					}
+				}
+
+				m, err := NewProcessor(d.Logger(), d.Context(), db).GetById(conditionSetId, uint32(version))
+				if err != nil {
+					writeError(d.Logger(), w, err, "Unable to retrieve condition set.")
+					return
+				}
+				marshal(d, c, w, r, m)
+			}
+		})
+	}
+}
+
+func handleCreateConditionSet(db *bbolt.DB) rest.InputHandler[RestModel] {
+	return func(d *rest.HandlerDependency, c *rest.HandlerContext, im RestModel) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			m, err := NewProcessor(d.Logger(), d.Context(), db).Create(im.Name, im.Description, im.Conditions)
+			if err != nil {
+				writeError(d.Logger(), w, err, "Unable to create condition set.")
+				return
+			}
+			marshal(d, c, w, r, m)
+		}
+	}
+}
+
+// handleUpdateConditionSet stores a new version of the set. Omitted attributes carry over from the latest version.
+func handleUpdateConditionSet(db *bbolt.DB) rest.InputHandler[RestModel] {
+	return func(d *rest.HandlerDependency, c *rest.HandlerContext, im RestModel) http.HandlerFunc {
+		return rest.ParseConditionSetId(d.Logger(), func(conditionSetId uuid.UUID) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				m, err := NewProcessor(d.Logger(), d.Context(), db).Update(conditionSetId, im.Name, im.Description, im.Conditions)
+				if err != nil {
+					writeError(d.Logger(), w, err, "Unable to update condition set.")
+					return
+				}
+				marshal(d, c, w, r, m)
+			}
+		})
+	}
+}
+
+func handleDeleteConditionSet(db *bbolt.DB) rest.GetHandler {
+	return func(d *rest.HandlerDependency, c *rest.HandlerContext) http.HandlerFunc {
+		return rest.ParseConditionSetId(d.Logger(), func(conditionSetId uuid.UUID) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				err := NewProcessor(d.Logger(), d.Context(), db).Delete(conditionSetId)
+				if err != nil {
+					writeError(d.Logger(), w, err, "Unable to delete condition set.")
+					return
+				}
+				w.WriteHeader(http.StatusNoContent)
+			}
+		})
+	}
+}
+
+func marshal(d *rest.HandlerDependency, c *rest.HandlerContext, w http.ResponseWriter, r *http.Request, m Model) {
+	res, err := model.Map(Transform)(model.FixedProvider(m))()
+	if err != nil {
+		d.Logger().WithError(err).Errorf("Creating REST model.")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	query := r.URL.Query()
+	queryParams := jsonapi.ParseQueryFields(&query)
+	server.MarshalResponse[RestModel](d.Logger())(w)(c.ServerInformation())(queryParams)(res)
+}
+
+// writeError maps processor errors onto response status codes
+func writeError(l logrus.FieldLogger, w http.ResponseWriter, err error, message string) {
+	l.WithError(err).Errorln(message)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Is(err, ErrInvalid):
+		w.WriteHeader(http.StatusBadRequest)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
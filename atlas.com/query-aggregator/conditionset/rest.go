@@ -0,0 +1,74 @@
+package conditionset
+
+import (
+	"atlas-query-aggregator/validation"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	Resource = "condition-sets"
+)
+
+// RestModel represents the REST model for condition sets
+//
+// Example create request:
+//   {
+//     "data": {
+//       "type": "condition-sets",
+//       "attributes": {
+//         "name": "henesys-pq-entry",
+//         "description": "Entry requirements for the Henesys party quest",
+//         "conditions": [
+//           { "type": "level", "operator": ">=", "value": 10 },
+//           { "type": "item", "operator": ">=", "value": 1, "referenceId": 4001095 }
+//         ]
+//       }
+//     }
+//   }
+type RestModel struct {
+	Id            uuid.UUID                   `json:"-"`
+	Name          string                      `json:"name"`
+	Description   string                      `json:"description,omitempty"`
+	Version       uint32                      `json:"version"`
+	LatestVersion uint32                      `json:"latestVersion"`
+	Conditions    []validation.ConditionInput `json:"conditions,omitempty"`
+	CreatedAt     time.Time                   `json:"createdAt"`
+}
+
+// GetName returns the resource name
+func (r RestModel) GetName() string {
+	return Resource
+}
+
+// GetID returns the resource ID
+func (r RestModel) GetID() string {
+	return r.Id.String()
+}
+
+// SetID sets the resource ID
+func (r *RestModel) SetID(idStr string) error {
+	if idStr == "" {
+		return nil
+	}
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return err
+	}
+	r.Id = id
+	return nil
+}
+
+// Transform converts a domain model to a REST model
+func Transform(m Model) (RestModel, error) {
+	return RestModel{
+		Id:            m.Id(),
+		Name:          m.Name(),
+		Description:   m.Description(),
+		Version:       m.Version(),
+		LatestVersion: m.LatestVersion(),
+		Conditions:    m.Conditions(),
+		CreatedAt:     m.CreatedAt(),
+	}, nil
+}
@@ -0,0 +1,19 @@
+package configuration
+
+import (
+	"atlas-query-aggregator/database"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"go.etcd.io/bbolt"
+)
+
+func put(db *bbolt.DB, tenantId uuid.UUID, e entity) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return database.UpdateTenant(db, collection, tenantId, func(b *bbolt.Bucket) error {
+		return b.Put([]byte(key), data)
+	})
+}
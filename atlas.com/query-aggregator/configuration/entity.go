@@ -0,0 +1,24 @@
+package configuration
+
+const (
+	collection = "configurations"
+	key        = "configuration"
+)
+
+// entity is the persisted form of a tenant configuration
+type entity struct {
+	MapGroups map[string][]uint32 `json:"mapGroups"`
+	Timezone  string              `json:"timezone"`
+}
+
+// Make converts the entity into a model
+func Make(e entity) (Model, error) {
+	mapGroups := e.MapGroups
+	if mapGroups == nil {
+		mapGroups = make(map[string][]uint32)
+	}
+	return Model{
+		mapGroups: mapGroups,
+		timezone:  e.Timezone,
+	}, nil
+}
@@ -0,0 +1,24 @@
+package configuration
+
+// Model represents the validation configuration of a tenant
+type Model struct {
+	mapGroups map[string][]uint32
+	timezone  string
+}
+
+// MapGroups returns the named groups of maps conditions can reference, e.g. "freeMarket"
+func (m Model) MapGroups() map[string][]uint32 {
+	return m.mapGroups
+}
+
+// Timezone returns the IANA name of the timezone time conditions are evaluated in, e.g. "America/New_York". An empty
+// timezone is UTC.
+func (m Model) Timezone() string {
+	return m.timezone
+}
+
+// MapGroup returns the maps of a named group, and whether the group is configured
+func (m Model) MapGroup(name string) ([]uint32, bool) {
+	maps, ok := m.mapGroups[name]
+	return maps, ok
+}
@@ -0,0 +1,111 @@
+package configuration
+
+import (
+	"atlas-query-aggregator/validation"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/Chronicle20/atlas-model/model"
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/sirupsen/logrus"
+	"go.etcd.io/bbolt"
+)
+
+// ErrInvalid is returned when a configuration fails validation
+var ErrInvalid = errors.New("invalid configuration")
+
+// Processor defines the interface for tenant configuration operations
+type Processor interface {
+	// ByTenantProvider provides the configuration of the tenant
+	ByTenantProvider() model.Provider[Model]
+
+	// Get retrieves the configuration of the tenant
+	Get() (Model, error)
+
+	// Update replaces the configuration of the tenant
+	Update(mapGroups map[string][]uint32, timezone string) (Model, error)
+}
+
+// ProcessorImpl implements the Processor interface
+type ProcessorImpl struct {
+	l   logrus.FieldLogger
+	ctx context.Context
+	db  *bbolt.DB
+	t   tenant.Model
+}
+
+// NewProcessor creates a new tenant configuration processor
+func NewProcessor(l logrus.FieldLogger, ctx context.Context, db *bbolt.DB) Processor {
+	return &ProcessorImpl{
+		l:   l,
+		ctx: ctx,
+		db:  db,
+		t:   tenant.MustFromContext(ctx),
+	}
+}
+
+// ByTenantProvider provides the configuration of the tenant
+func (p *ProcessorImpl) ByTenantProvider() model.Provider[Model] {
+	return func() (Model, error) {
+		e, err := get(p.db, p.t.Id())
+		if err != nil {
+			return Model{}, err
+		}
+		return Make(e)
+	}
+}
+
+// Get retrieves the configuration of the tenant
+func (p *ProcessorImpl) Get() (Model, error) {
+	return p.ByTenantProvider()()
+}
+
+// Update replaces the configuration of the tenant
+func (p *ProcessorImpl) Update(mapGroups map[string][]uint32, timezone string) (Model, error) {
+	if err := validateMapGroups(mapGroups); err != nil {
+		return Model{}, err
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return Model{}, fmt.Errorf("%w: unknown timezone %s", ErrInvalid, timezone)
+	}
+
+	e := entity{MapGroups: mapGroups, Timezone: timezone}
+	if err := put(p.db, p.t.Id(), e); err != nil {
+		return Model{}, err
+	}
+	p.l.Debugf("Updated configuration of tenant [%s].", p.t.Id())
+	return Make(e)
+}
+
+// validateMapGroups checks that every map group is named and holds at least one map
+func validateMapGroups(mapGroups map[string][]uint32) error {
+	for name, maps := range mapGroups {
+		if name == "" {
+			return fmt.Errorf("%w: map group name is required", ErrInvalid)
+		}
+		if len(maps) == 0 {
+			return fmt.Errorf("%w: map group %s requires at least one map", ErrInvalid, name)
+		}
+	}
+	return nil
+}
+
+// Resolver gives validation requests access to the configuration of the requesting tenant
+func Resolver(db *bbolt.DB) validation.ConfigurationResolverProvider {
+	return func(l logrus.FieldLogger, ctx context.Context) validation.ConfigurationResolver {
+		return func() (validation.TenantConfiguration, error) {
+			m, err := NewProcessor(l, ctx, db).Get()
+			if err != nil {
+				return validation.TenantConfiguration{}, err
+			}
+			location, err := time.LoadLocation(m.Timezone())
+			if err != nil {
+				return validation.TenantConfiguration{}, err
+			}
+			return validation.NewTenantConfiguration(m.MapGroups(), location), nil
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package configuration
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"go.etcd.io/bbolt"
+)
+
+func setupTest(t *testing.T) (*bbolt.DB, context.Context) {
+	db, err := bbolt.Open(filepath.Join(t.TempDir(), "test.db"), 0600, nil)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	tm, err := tenant.Create(uuid.New(), "GMS", 83, 1)
+	if err != nil {
+		t.Fatalf("Failed to create tenant: %v", err)
+	}
+	return db, tenant.WithContext(context.Background(), tm)
+}
+
+func TestProcessor_MapGroups(t *testing.T) {
+	db, ctx := setupTest(t)
+	p := NewProcessor(logrus.New(), ctx, db)
+
+	empty, err := p.Get()
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if len(empty.MapGroups()) != 0 {
+		t.Errorf("Expected no map groups before any update, got %v", empty.MapGroups())
+	}
+
+	groups := map[string][]uint32{"freeMarket": {910000001, 910000002}, "pqLobby": {103000000}}
+	if _, err = p.Update(groups, "America/New_York"); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	stored, err := p.Get()
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if !reflect.DeepEqual(stored.MapGroups(), groups) {
+		t.Errorf("Expected map groups %v, got %v", groups, stored.MapGroups())
+	}
+	if stored.Timezone() != "America/New_York" {
+		t.Errorf("Expected timezone America/New_York, got %s", stored.Timezone())
+	}
+	if maps, ok := stored.MapGroup("pqLobby"); !ok || len(maps) != 1 {
+		t.Errorf("Expected the pqLobby group, got %v, %v", maps, ok)
+	}
+
+	resolved, err := Resolver(db)(logrus.New(), ctx)()
+	if err != nil {
+		t.Fatalf("Resolver failed: %v", err)
+	}
+	if maps, ok := resolved.MapGroup("freeMarket"); !ok || !reflect.DeepEqual(maps, groups["freeMarket"]) {
+		t.Errorf("Expected the resolved freeMarket group, got %v, %v", maps, ok)
+	}
+	if resolved.Location().String() != "America/New_York" {
+		t.Errorf("Expected the resolved location America/New_York, got %s", resolved.Location())
+	}
+}
+
+func TestProcessor_Validation(t *testing.T) {
+	db, ctx := setupTest(t)
+	p := NewProcessor(logrus.New(), ctx, db)
+
+	tests := []struct {
+		name      string
+		mapGroups map[string][]uint32
+		timezone  string
+	}{
+		{name: "Missing name", mapGroups: map[string][]uint32{"": {100000000}}},
+		{name: "Empty group", mapGroups: map[string][]uint32{"freeMarket": {}}},
+		{name: "Unknown timezone", timezone: "Maple/Henesys"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := p.Update(tt.mapGroups, tt.timezone); !errors.Is(err, ErrInvalid) {
+				t.Errorf("Expected ErrInvalid, got %v", err)
+			}
+		})
+	}
+}
+
+func TestProcessor_TenantIsolation(t *testing.T) {
+	db, ctx := setupTest(t)
+	if _, err := NewProcessor(logrus.New(), ctx, db).Update(map[string][]uint32{"freeMarket": {910000001}}, ""); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	other, err := tenant.Create(uuid.New(), "GMS", 83, 1)
+	if err != nil {
+		t.Fatalf("Failed to create tenant: %v", err)
+	}
+	m, err := NewProcessor(logrus.New(), tenant.WithContext(context.Background(), other), db).Get()
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if _, ok := m.MapGroup("freeMarket"); ok {
+		t.Error("Expected no map groups for another tenant")
+	}
+}
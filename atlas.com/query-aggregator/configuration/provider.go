@@ -0,0 +1,25 @@
+package configuration
+
+import (
+	"atlas-query-aggregator/database"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"go.etcd.io/bbolt"
+)
+
+// get retrieves the tenant configuration. A tenant that has stored no configuration has an empty one.
+func get(db *bbolt.DB, tenantId uuid.UUID) (entity, error) {
+	var result entity
+	err := database.ViewTenant(db, collection, tenantId, func(b *bbolt.Bucket) error {
+		if b == nil {
+			return nil
+		}
+		data := b.Get([]byte(key))
+		if data == nil {
+			return nil
+		}
+		return json.Unmarshal(data, &result)
+	})
+	return result, err
+}
@@ -0,0 +1,92 @@
+package configuration
+
+import (
+	"atlas-query-aggregator/rest"
+	"errors"
+	"net/http"
+
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/Chronicle20/atlas-rest/server"
+	"github.com/gorilla/mux"
+	"github.com/jtumidanski/api2go/jsonapi"
+	"github.com/sirupsen/logrus"
+	"go.etcd.io/bbolt"
+)
+
+// InitResource registers the routes with the router
+func InitResource(si jsonapi.ServerInformation) func(db *bbolt.DB) server.RouteInitializer {
+	return func(db *bbolt.DB) server.RouteInitializer {
+		return func(r *mux.Router, l logrus.FieldLogger) {
+			router := r.PathPrefix("/configuration").Subrouter()
+			router.HandleFunc("", rest.RegisterHandler(l)(si)("get_configuration", handleGetConfiguration(db))).Methods(http.MethodGet)
+			router.HandleFunc("", rest.RegisterInputHandler[RestModel](l)(si)("update_configuration", handleUpdateConfiguration(db))).Methods(http.MethodPatch)
+		}
+	}
+}
+
+func handleGetConfiguration(db *bbolt.DB) rest.GetHandler {
+	return func(d *rest.HandlerDependency, c *rest.HandlerContext) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			m, err := NewProcessor(d.Logger(), d.Context(), db).Get()
+			if err != nil {
+				writeError(d.Logger(), w, err, "Unable to retrieve configuration.")
+				return
+			}
+			marshal(d, c, w, r, m)
+		}
+	}
+}
+
+// handleUpdateConfiguration replaces the configuration. Omitted attributes carry over from the current configuration.
+func handleUpdateConfiguration(db *bbolt.DB) rest.InputHandler[RestModel] {
+	return func(d *rest.HandlerDependency, c *rest.HandlerContext, im RestModel) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			p := NewProcessor(d.Logger(), d.Context(), db)
+			current, err := p.Get()
+			if err != nil {
+				writeError(d.Logger(), w, err, "Unable to retrieve configuration.")
+				return
+			}
+
+			mapGroups := current.MapGroups()
+			if im.MapGroups != nil {
+				mapGroups = im.MapGroups
+			}
+			timezone := current.Timezone()
+			if im.Timezone != nil {
+				timezone = *im.Timezone
+			}
+
+			m, err := p.Update(mapGroups, timezone)
+			if err != nil {
+				writeError(d.Logger(), w, err, "Unable to update configuration.")
+				return
+			}
+			marshal(d, c, w, r, m)
+		}
+	}
+}
+
+func marshal(d *rest.HandlerDependency, c *rest.HandlerContext, w http.ResponseWriter, r *http.Request, m Model) {
+	res, err := model.Map(Transform)(model.FixedProvider(m))()
+	if err != nil {
+		d.Logger().WithError(err).Errorf("Creating REST model.")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	query := r.URL.Query()
+	queryParams := jsonapi.ParseQueryFields(&query)
+	server.MarshalResponse[RestModel](d.Logger())(w)(c.ServerInformation())(queryParams)(res)
+}
+
+// writeError maps processor errors onto response status codes
+func writeError(l logrus.FieldLogger, w http.ResponseWriter, err error, message string) {
+	l.WithError(err).Errorln(message)
+	switch {
+	case errors.Is(err, ErrInvalid):
+		w.WriteHeader(http.StatusBadRequest)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
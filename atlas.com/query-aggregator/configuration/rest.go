@@ -0,0 +1,51 @@
+package configuration
+
+const (
+	Resource = "configurations"
+)
+
+// RestModel represents the REST model for the configuration of a tenant
+//
+// Example update request:
+//   {
+//     "data": {
+//       "type": "configurations",
+//       "attributes": {
+//         "mapGroups": {
+//           "freeMarket": [910000001, 910000002, 910000003],
+//           "pqLobby": [103000000, 221024500]
+//         },
+//         "timezone": "America/New_York"
+//       }
+//     }
+//   }
+type RestModel struct {
+	Id        string              `json:"-"`
+	MapGroups map[string][]uint32 `json:"mapGroups"`          // Named groups of maps referenced by mapIn conditions
+	Timezone  *string             `json:"timezone,omitempty"` // IANA timezone time conditions are evaluated in; UTC when empty
+}
+
+// GetName returns the resource name
+func (r RestModel) GetName() string {
+	return Resource
+}
+
+// GetID returns the resource ID
+func (r RestModel) GetID() string {
+	return r.Id
+}
+
+// SetID sets the resource ID. The configuration is identified by the requesting tenant, so any ID is accepted.
+func (r *RestModel) SetID(idStr string) error {
+	r.Id = idStr
+	return nil
+}
+
+// Transform converts a domain model to a REST model
+func Transform(m Model) (RestModel, error) {
+	timezone := m.Timezone()
+	return RestModel{
+		MapGroups: m.MapGroups(),
+		Timezone:  &timezone,
+	}, nil
+}
@@ -0,0 +1,68 @@
+package database
+
+import (
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"go.etcd.io/bbolt"
+)
+
+const defaultPath = "query-aggregator.db"
+
+// Connect opens the embedded store holding data owned by this service, creating it if needed
+func Connect(l logrus.FieldLogger) *bbolt.DB {
+	path, ok := os.LookupEnv("DB_PATH")
+	if !ok || path == "" {
+		path = defaultPath
+	}
+
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		l.WithError(err).Fatalf("Unable to open database [%s].", path)
+	}
+	l.Infof("Opened database [%s].", path)
+	return db
+}
+
+func Teardown(l logrus.FieldLogger) func(db *bbolt.DB) func() {
+	return func(db *bbolt.DB) func() {
+		return func() {
+			err := db.Close()
+			if err != nil {
+				l.WithError(err).Errorf("Unable to close database.")
+			}
+		}
+	}
+}
+
+// BucketHandler operates on the bucket holding one tenant's records of a collection
+type BucketHandler func(b *bbolt.Bucket) error
+
+// ViewTenant runs a read-only transaction against the tenant's bucket for the collection.
+// The bucket is nil when the tenant has not stored anything in the collection.
+func ViewTenant(db *bbolt.DB, collection string, tenantId uuid.UUID, f BucketHandler) error {
+	return db.View(func(tx *bbolt.Tx) error {
+		root := tx.Bucket([]byte(collection))
+		if root == nil {
+			return f(nil)
+		}
+		return f(root.Bucket([]byte(tenantId.String())))
+	})
+}
+
+// UpdateTenant runs a read-write transaction against the tenant's bucket for the collection, creating it as needed
+func UpdateTenant(db *bbolt.DB, collection string, tenantId uuid.UUID, f BucketHandler) error {
+	return db.Update(func(tx *bbolt.Tx) error {
+		root, err := tx.CreateBucketIfNotExists([]byte(collection))
+		if err != nil {
+			return err
+		}
+		b, err := root.CreateBucketIfNotExists([]byte(tenantId.String()))
+		if err != nil {
+			return err
+		}
+		return f(b)
+	})
+}
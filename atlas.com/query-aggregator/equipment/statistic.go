@@ -0,0 +1,75 @@
+package equipment
+
+import (
+	"atlas-query-aggregator/asset"
+	slot2 "github.com/Chronicle20/atlas-constants/inventory/slot"
+)
+
+// Statistic identifies a statistic equipped items grant bonuses to
+type Statistic string
+
+const (
+	Strength      Statistic = "strength"
+	Dexterity     Statistic = "dexterity"
+	Intelligence  Statistic = "intelligence"
+	Luck          Statistic = "luck"
+	HP            Statistic = "hp"
+	MP            Statistic = "mp"
+	WeaponAttack  Statistic = "weaponAttack"
+	MagicAttack   Statistic = "magicAttack"
+	WeaponDefense Statistic = "weaponDefense"
+	MagicDefense  Statistic = "magicDefense"
+	Accuracy      Statistic = "accuracy"
+	Avoidability  Statistic = "avoidability"
+	Speed         Statistic = "speed"
+	Jump          Statistic = "jump"
+)
+
+var statistics = map[Statistic]func(asset.StatisticData) uint16{
+	Strength:      asset.StatisticData.Strength,
+	Dexterity:     asset.StatisticData.Dexterity,
+	Intelligence:  asset.StatisticData.Intelligence,
+	Luck:          asset.StatisticData.Luck,
+	HP:            asset.StatisticData.HP,
+	MP:            asset.StatisticData.MP,
+	WeaponAttack:  asset.StatisticData.WeaponAttack,
+	MagicAttack:   asset.StatisticData.MagicAttack,
+	WeaponDefense: asset.StatisticData.WeaponDefense,
+	MagicDefense:  asset.StatisticData.MagicDefense,
+	Accuracy:      asset.StatisticData.Accuracy,
+	Avoidability:  asset.StatisticData.Avoidability,
+	Speed:         asset.StatisticData.Speed,
+	Jump:          asset.StatisticData.Jump,
+}
+
+// Bonuses returns the bonus the items equipped in each slot, regular and cash, grant to the statistic.
+// Slots granting no bonus are omitted.
+func (m Model) Bonuses(s Statistic) map[slot2.Type]int {
+	result := make(map[slot2.Type]int)
+	read, ok := statistics[s]
+	if !ok {
+		return result
+	}
+	for t, v := range m.slots {
+		bonus := 0
+		if v.Equipable != nil {
+			bonus += int(read(v.Equipable.ReferenceData().StatisticData))
+		}
+		if v.CashEquipable != nil {
+			bonus += int(read(v.CashEquipable.ReferenceData().StatisticData))
+		}
+		if bonus != 0 {
+			result[t] = bonus
+		}
+	}
+	return result
+}
+
+// Bonus returns the total bonus every equipped item grants to the statistic
+func (m Model) Bonus(s Statistic) int {
+	total := 0
+	for _, bonus := range m.Bonuses(s) {
+		total += bonus
+	}
+	return total
+}
@@ -82,3 +82,37 @@ func (m Model) MemberRank(characterId uint32) int {
 	}
 	return 0
 }
+
+// OnlineMemberCount returns the number of members currently online
+func (m Model) OnlineMemberCount() int {
+	count := 0
+	for _, mem := range m.Members() {
+		if mem.Online() {
+			count++
+		}
+	}
+	return count
+}
+
+// MembersAtLevel returns the number of members at or above the level
+func (m Model) MembersAtLevel(level byte) int {
+	count := 0
+	for _, mem := range m.Members() {
+		if mem.Level() >= level {
+			count++
+		}
+	}
+	return count
+}
+
+// MembersAtRank returns the number of members at or above the rank. Rank 1 is the guild master, so higher ranks have
+// lower numbers.
+func (m Model) MembersAtRank(rank byte) int {
+	count := 0
+	for _, mem := range m.Members() {
+		if mem.Rank() != 0 && mem.Rank() <= rank {
+			count++
+		}
+	}
+	return count
+}
@@ -131,4 +131,35 @@ func TestMemberRank(t *testing.T) {
 	if rank != 0 {
 		t.Errorf("expected rank 0 for non-member, got %d", rank)
 	}
-}
\ No newline at end of file
+}
+func TestMemberCounts(t *testing.T) {
+	rm := RestModel{
+		Id: 123,
+		Members: []member.RestModel{
+			{CharacterId: 1, Level: 120, Rank: 1, Online: true},
+			{CharacterId: 2, Level: 70, Rank: 2, Online: false},
+			{CharacterId: 3, Level: 69, Rank: 3, Online: true},
+			{CharacterId: 4, Level: 30, Rank: 5, Online: false},
+		},
+	}
+	guild, err := Extract(rm)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := len(guild.Members()); got != 4 {
+		t.Errorf("expected 4 members, got %d", got)
+	}
+	if got := guild.OnlineMemberCount(); got != 2 {
+		t.Errorf("expected 2 online members, got %d", got)
+	}
+	if got := guild.MembersAtLevel(70); got != 2 {
+		t.Errorf("expected 2 members at or above level 70, got %d", got)
+	}
+	if got := guild.MembersAtRank(2); got != 2 {
+		t.Errorf("expected 2 members at or above rank 2, got %d", got)
+	}
+	if got := guild.MembersAtRank(5); got != 4 {
+		t.Errorf("expected 4 members at or above rank 5, got %d", got)
+	}
+}
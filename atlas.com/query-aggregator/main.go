@@ -1,6 +1,9 @@
 package main
 
 import (
+	"atlas-query-aggregator/conditionset"
+	"atlas-query-aggregator/configuration"
+	"atlas-query-aggregator/database"
 	"atlas-query-aggregator/logger"
 	"atlas-query-aggregator/service"
 	"atlas-query-aggregator/tracing"
@@ -42,16 +45,21 @@ func main() {
 		l.WithError(err).Fatal("Unable to initialize tracer.")
 	}
 
+	db := database.Connect(l)
+
 	// Create server
 	server.New(l).
 		WithContext(tdm.Context()).
 		WithWaitGroup(tdm.WaitGroup()).
 		SetBasePath(GetServer().GetPrefix()).
 		SetPort(os.Getenv("REST_PORT")).
-		AddRouteInitializer(validation.InitResource(GetServer())).
+		AddRouteInitializer(validation.InitResource(GetServer())(conditionset.Resolver(db), configuration.Resolver(db))).
+		AddRouteInitializer(conditionset.InitResource(GetServer())(db)).
+		AddRouteInitializer(configuration.InitResource(GetServer())(db)).
 		Run()
 
 	tdm.TeardownFunc(tracing.Teardown(l)(tc))
+	tdm.TeardownFunc(database.Teardown(l)(db))
 
 	tdm.Wait()
 	l.Infoln("Service shutdown.")
@@ -10,6 +10,7 @@ type ProcessorImpl struct {
 	GetQuestStatusFunc   func(characterId uint32, questId uint32) model.Provider[quest.QuestStatus]
 	GetQuestProgressFunc func(characterId uint32, questId uint32, step string) model.Provider[int]
 	GetQuestFunc         func(characterId uint32, questId uint32) model.Provider[quest.Model]
+	GetQuestsFunc        func(characterId uint32) model.Provider[map[uint32]quest.Model]
 }
 
 // GetQuestStatus returns the status of a quest for a character
@@ -40,4 +41,14 @@ func (m *ProcessorImpl) GetQuest(characterId uint32, questId uint32) model.Provi
 	return func() (quest.Model, error) {
 		return quest.NewModel(questId, quest.UNDEFINED), nil
 	}
+}
+
+// GetQuests returns the status and progress of every quest the character has
+func (m *ProcessorImpl) GetQuests(characterId uint32) model.Provider[map[uint32]quest.Model] {
+	if m.GetQuestsFunc != nil {
+		return m.GetQuestsFunc(characterId)
+	}
+	return func() (map[uint32]quest.Model, error) {
+		return make(map[uint32]quest.Model), nil
+	}
 }
\ No newline at end of file
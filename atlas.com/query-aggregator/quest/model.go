@@ -1,5 +1,7 @@
 package quest
 
+import "time"
+
 // QuestStatus represents the status of a quest
 type QuestStatus int
 
@@ -48,9 +50,12 @@ func FromString(s string) QuestStatus {
 
 // Model represents a quest and its progress
 type Model struct {
-	id       uint32
-	status   QuestStatus
-	progress map[string]int
+	id           uint32
+	status       QuestStatus
+	progress     map[string]int
+	startedAt    time.Time // Zero when the quest was never started
+	completedAt  time.Time // Zero when the quest was never completed; the last completion of repeatable quests
+	forfeitCount uint32
 }
 
 // NewModel creates a new quest model
@@ -72,6 +77,21 @@ func (m Model) Status() QuestStatus {
 	return m.status
 }
 
+// StartedAt returns when the quest was last started, or the zero time if it never was
+func (m Model) StartedAt() time.Time {
+	return m.startedAt
+}
+
+// CompletedAt returns when the quest was last completed, or the zero time if it never was
+func (m Model) CompletedAt() time.Time {
+	return m.completedAt
+}
+
+// ForfeitCount returns the number of times the quest was forfeited
+func (m Model) ForfeitCount() uint32 {
+	return m.forfeitCount
+}
+
 // Progress returns the progress for a specific step
 func (m Model) Progress(step string) int {
 	if progress, exists := m.progress[step]; exists {
@@ -88,18 +108,18 @@ func (m Model) SetProgress(step string, value int) Model {
 	}
 	newProgress[step] = value
 	
-	return Model{
-		id:       m.id,
-		status:   m.status,
-		progress: newProgress,
-	}
+	m.progress = newProgress
+	return m
 }
 
 // ModelBuilder provides a builder pattern for creating quest models
 type ModelBuilder struct {
-	id       uint32
-	status   QuestStatus
-	progress map[string]int
+	id           uint32
+	status       QuestStatus
+	progress     map[string]int
+	startedAt    time.Time
+	completedAt  time.Time
+	forfeitCount uint32
 }
 
 // NewModelBuilder creates a new quest model builder
@@ -130,32 +150,32 @@ func (b *ModelBuilder) SetProgress(step string, value int) *ModelBuilder {
 	return b
 }
 
-// Build creates a quest model from the builder
-func (b *ModelBuilder) Build() Model {
-	return Model{
-		id:       b.id,
-		status:   b.status,
-		progress: b.progress,
-	}
+// SetStartedAt sets when the quest was last started
+func (b *ModelBuilder) SetStartedAt(startedAt time.Time) *ModelBuilder {
+	b.startedAt = startedAt
+	return b
 }
 
-// RestModel represents the REST representation of a quest
-type RestModel struct {
-	Id       uint32         `json:"id"`
-	Status   string         `json:"status"`
-	Progress map[string]int `json:"progress"`
+// SetCompletedAt sets when the quest was last completed
+func (b *ModelBuilder) SetCompletedAt(completedAt time.Time) *ModelBuilder {
+	b.completedAt = completedAt
+	return b
 }
 
-// Extract transforms a RestModel into a domain Model
-func Extract(r RestModel) (Model, error) {
-	builder := NewModelBuilder().
-		SetId(r.Id).
-		SetStatus(FromString(r.Status))
-	
-	// Set progress for each step
-	for step, value := range r.Progress {
-		builder = builder.SetProgress(step, value)
+// SetForfeitCount sets the number of times the quest was forfeited
+func (b *ModelBuilder) SetForfeitCount(forfeitCount uint32) *ModelBuilder {
+	b.forfeitCount = forfeitCount
+	return b
+}
+
+// Build creates a quest model from the builder
+func (b *ModelBuilder) Build() Model {
+	return Model{
+		id:           b.id,
+		status:       b.status,
+		progress:     b.progress,
+		startedAt:    b.startedAt,
+		completedAt:  b.completedAt,
+		forfeitCount: b.forfeitCount,
 	}
-	
-	return builder.Build(), nil
 }
\ No newline at end of file
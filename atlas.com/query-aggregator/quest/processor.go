@@ -12,6 +12,7 @@ type Processor interface {
 	GetQuestStatus(characterId uint32, questId uint32) model.Provider[QuestStatus]
 	GetQuestProgress(characterId uint32, questId uint32, step string) model.Provider[int]
 	GetQuest(characterId uint32, questId uint32) model.Provider[Model]
+	GetQuests(characterId uint32) model.Provider[map[uint32]Model]
 }
 
 // processor implements the Processor interface
@@ -65,4 +66,20 @@ func (p *processor) GetQuest(characterId uint32, questId uint32) model.Provider[
 		}
 		return quest, nil
 	}
+}
+
+// GetQuests returns the status and progress of every quest the character has, keyed by quest ID, in a single request
+func (p *processor) GetQuests(characterId uint32) model.Provider[map[uint32]Model] {
+	return func() (map[uint32]Model, error) {
+		quests, err := requests.SliceProvider[RestModel, Model](p.l, p.ctx)(requestByCharacterId(characterId), Extract, model.Filters[Model]())()
+		if err != nil {
+			p.l.WithError(err).Errorf("Failed to get quests for character %d", characterId)
+			return nil, err
+		}
+		questsMap := make(map[uint32]Model, len(quests))
+		for _, q := range quests {
+			questsMap[q.Id()] = q
+		}
+		return questsMap, nil
+	}
 }
\ No newline at end of file
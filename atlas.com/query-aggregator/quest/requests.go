@@ -7,8 +7,9 @@ import (
 )
 
 const (
-	Resource = "quests"
-	ById     = Resource + "/%d"
+	Resource      = "quests"
+	ById          = Resource + "/%d"
+	ByCharacterId = Resource + "?characterId=%d"
 )
 
 func getBaseRequest() string {
@@ -17,4 +18,8 @@ func getBaseRequest() string {
 
 func requestById(characterId uint32, questId uint32) requests.Request[RestModel] {
 	return rest.MakeGetRequest[RestModel](fmt.Sprintf(getBaseRequest()+ById+"?characterId=%d", questId, characterId))
+}
+
+func requestByCharacterId(characterId uint32) requests.Request[[]RestModel] {
+	return rest.MakeGetRequest[[]RestModel](fmt.Sprintf(getBaseRequest()+ByCharacterId, characterId))
 }
\ No newline at end of file
@@ -0,0 +1,50 @@
+package quest
+
+import (
+	"strconv"
+	"time"
+)
+
+// RestModel represents the REST representation of the status of a quest for a character
+type RestModel struct {
+	Id           uint32         `json:"-"`
+	Status       string         `json:"status"`
+	Progress     map[string]int `json:"progress"`
+	StartedAt    time.Time      `json:"startedAt"`
+	CompletedAt  time.Time      `json:"completedAt"`
+	ForfeitCount uint32         `json:"forfeitCount"`
+}
+
+func (r RestModel) GetName() string {
+	return Resource
+}
+
+func (r RestModel) GetID() string {
+	return strconv.Itoa(int(r.Id))
+}
+
+func (r *RestModel) SetID(strId string) error {
+	id, err := strconv.Atoi(strId)
+	if err != nil {
+		return err
+	}
+	r.Id = uint32(id)
+	return nil
+}
+
+// Extract transforms a RestModel into a domain Model
+func Extract(r RestModel) (Model, error) {
+	builder := NewModelBuilder().
+		SetId(r.Id).
+		SetStatus(FromString(r.Status)).
+		SetStartedAt(r.StartedAt).
+		SetCompletedAt(r.CompletedAt).
+		SetForfeitCount(r.ForfeitCount)
+
+	// Set progress for each step
+	for step, value := range r.Progress {
+		builder = builder.SetProgress(step, value)
+	}
+
+	return builder.Build(), nil
+}
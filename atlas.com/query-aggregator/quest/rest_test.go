@@ -0,0 +1,29 @@
+package quest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExtract(t *testing.T) {
+	startedAt := time.Date(2026, time.October, 16, 9, 0, 0, 0, time.UTC)
+	completedAt := time.Date(2026, time.October, 16, 10, 30, 0, 0, time.UTC)
+
+	m, err := Extract(RestModel{
+		Id:           1001,
+		Status:       "COMPLETED",
+		Progress:     map[string]int{"mobsKilled": 10},
+		StartedAt:    startedAt,
+		CompletedAt:  completedAt,
+		ForfeitCount: 3,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if m.Id() != 1001 || m.Status() != COMPLETED || m.Progress("mobsKilled") != 10 {
+		t.Errorf("Extract() = %+v", m)
+	}
+	if !m.StartedAt().Equal(startedAt) || !m.CompletedAt().Equal(completedAt) || m.ForfeitCount() != 3 {
+		t.Errorf("StartedAt = %v, CompletedAt = %v, ForfeitCount = %d", m.StartedAt(), m.CompletedAt(), m.ForfeitCount())
+	}
+}
@@ -0,0 +1,37 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrorSource identifies the part of the request document an error belongs to
+type ErrorSource struct {
+	Pointer string `json:"pointer"` // JSON Pointer to the offending value, e.g. /data/attributes/conditions/0
+}
+
+// ErrorRestModel represents a JSON:API error object
+type ErrorRestModel struct {
+	Status string                 `json:"status"`
+	Title  string                 `json:"title"`
+	Detail string                 `json:"detail"`
+	Source *ErrorSource           `json:"source,omitempty"`
+	Meta   map[string]interface{} `json:"meta,omitempty"`
+}
+
+// WriteErrors writes a JSON:API error document with the status
+func WriteErrors(l logrus.FieldLogger, w http.ResponseWriter, status int, errs ...ErrorRestModel) {
+	for i := range errs {
+		errs[i].Status = strconv.Itoa(status)
+	}
+	w.Header().Set("Content-Type", "application/vnd.api+json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(struct {
+		Errors []ErrorRestModel `json:"errors"`
+	}{Errors: errs}); err != nil {
+		l.WithError(err).Errorln("Writing error response")
+	}
+}
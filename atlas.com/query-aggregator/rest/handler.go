@@ -2,11 +2,15 @@ package rest
 
 import (
 	"context"
+	"github.com/Chronicle20/atlas-constants/world"
 	"github.com/Chronicle20/atlas-rest/server"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
 	"github.com/jtumidanski/api2go/jsonapi"
 	"github.com/sirupsen/logrus"
 	"io"
 	"net/http"
+	"strconv"
 )
 
 type HandlerDependency struct {
@@ -80,3 +84,48 @@ func RegisterInputHandler[M any](l logrus.FieldLogger) func(si jsonapi.ServerInf
 		}
 	}
 }
+
+type ConditionSetIdHandler func(conditionSetId uuid.UUID) http.HandlerFunc
+
+func ParseConditionSetId(l logrus.FieldLogger, next ConditionSetIdHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		conditionSetId, err := uuid.Parse(vars["conditionSetId"])
+		if err != nil {
+			l.WithError(err).Errorf("Unable to properly parse conditionSetId from path.")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		next(conditionSetId)(w, r)
+	}
+}
+
+type AccountIdHandler func(accountId uint32) http.HandlerFunc
+
+func ParseAccountId(l logrus.FieldLogger, next AccountIdHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		accountId, err := strconv.ParseUint(vars["accountId"], 10, 32)
+		if err != nil {
+			l.WithError(err).Errorf("Unable to properly parse accountId from path.")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		next(uint32(accountId))(w, r)
+	}
+}
+
+type WorldIdHandler func(worldId world.Id) http.HandlerFunc
+
+func ParseWorldId(l logrus.FieldLogger, next WorldIdHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		worldId, err := strconv.ParseUint(vars["worldId"], 10, 8)
+		if err != nil {
+			l.WithError(err).Errorf("Unable to properly parse worldId from path.")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		next(world.Id(worldId))(w, r)
+	}
+}
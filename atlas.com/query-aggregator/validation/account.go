@@ -0,0 +1,134 @@
+package validation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ScopeType represents how the validations of the characters on an account combine into the account outcome
+type ScopeType string
+
+const (
+	AllScope   ScopeType = "all"   // Passes when every character on the account passes
+	AnyScope   ScopeType = "any"   // Passes when at least one character on the account passes
+	CountScope ScopeType = "count" // Passes when at least N characters on the account pass, written count:N
+)
+
+// AccountScope describes how an account validation combines the validations of the characters on the account
+type AccountScope struct {
+	scopeType ScopeType
+	count     int // Required number of passing characters for the count scope
+}
+
+// ParseAccountScope parses an account scope. An empty scope is the all scope.
+func ParseAccountScope(scope string) (AccountScope, error) {
+	name, argument, hasArgument := strings.Cut(scope, ":")
+	s := AccountScope{scopeType: ScopeType(name)}
+	if name == "" {
+		s.scopeType = AllScope
+	}
+
+	switch s.scopeType {
+	case AllScope, AnyScope:
+		if hasArgument {
+			return AccountScope{}, fmt.Errorf("scope %s does not take an argument", s.scopeType)
+		}
+	case CountScope:
+		count, err := strconv.Atoi(argument)
+		if !hasArgument || err != nil || count < 1 {
+			return AccountScope{}, fmt.Errorf("scope count requires a positive count, e.g. count:2")
+		}
+		s.count = count
+	default:
+		return AccountScope{}, fmt.Errorf("unsupported scope: %s", scope)
+	}
+	return s, nil
+}
+
+// Type returns the scope type
+func (s AccountScope) Type() ScopeType {
+	return s.scopeType
+}
+
+// Count returns the number of characters required to pass in count scope
+func (s AccountScope) Count() int {
+	return s.count
+}
+
+// String returns the scope in its request form, e.g. "count:2"
+func (s AccountScope) String() string {
+	if s.scopeType == CountScope {
+		return fmt.Sprintf("%s:%d", s.scopeType, s.count)
+	}
+	return string(s.scopeType)
+}
+
+// decide returns whether the number of passing characters satisfies the scope. An account without characters never
+// passes.
+func (s AccountScope) decide(passedCount int, totalCount int) bool {
+	switch s.scopeType {
+	case AnyScope:
+		return passedCount > 0
+	case CountScope:
+		return passedCount >= s.count
+	default:
+		return totalCount > 0 && passedCount == totalCount
+	}
+}
+
+// AccountValidationResult represents the result of validating conditions against every character on an account
+type AccountValidationResult struct {
+	accountId   uint32
+	scope       AccountScope
+	passed      bool
+	passedCount int // Number of characters that passed
+	characters  []ValidationResult
+}
+
+// NewAccountValidationResult creates an account validation result, deciding the outcome from the character results
+func NewAccountValidationResult(accountId uint32, scope AccountScope, characters []ValidationResult) AccountValidationResult {
+	passedCount := 0
+	for _, c := range characters {
+		if c.Passed() {
+			passedCount++
+		}
+	}
+	return AccountValidationResult{
+		accountId:   accountId,
+		scope:       scope,
+		passed:      scope.decide(passedCount, len(characters)),
+		passedCount: passedCount,
+		characters:  characters,
+	}
+}
+
+// AccountId returns the account validated
+func (v AccountValidationResult) AccountId() uint32 {
+	return v.accountId
+}
+
+// Scope returns the scope deciding the outcome
+func (v AccountValidationResult) Scope() AccountScope {
+	return v.scope
+}
+
+// Passed returns whether the account validation passed
+func (v AccountValidationResult) Passed() bool {
+	return v.passed
+}
+
+// PassedCount returns the number of characters that passed
+func (v AccountValidationResult) PassedCount() int {
+	return v.passedCount
+}
+
+// TotalCount returns the number of characters validated
+func (v AccountValidationResult) TotalCount() int {
+	return len(v.characters)
+}
+
+// Characters returns the validation result of each character on the account
+func (v AccountValidationResult) Characters() []ValidationResult {
+	return v.characters
+}
@@ -0,0 +1,185 @@
+package validation
+
+import (
+	"atlas-query-aggregator/character"
+	"atlas-query-aggregator/character/mock"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Chronicle20/atlas-constants/world"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/sirupsen/logrus"
+)
+
+// TestParseAccountScope tests parsing of account scopes
+func TestParseAccountScope(t *testing.T) {
+	tests := []struct {
+		name          string
+		scope         string
+		wantType      ScopeType
+		wantCount     int
+		errorContains string
+	}{
+		{name: "Default", scope: "", wantType: AllScope},
+		{name: "All", scope: "all", wantType: AllScope},
+		{name: "Any", scope: "any", wantType: AnyScope},
+		{name: "Count", scope: "count:2", wantType: CountScope, wantCount: 2},
+		{name: "Count without count", scope: "count", errorContains: "scope count requires a positive count"},
+		{name: "Count with zero count", scope: "count:0", errorContains: "scope count requires a positive count"},
+		{name: "Argument for all", scope: "all:2", errorContains: "scope all does not take an argument"},
+		{name: "Unknown scope", scope: "most", errorContains: "unsupported scope: most"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ParseAccountScope(tt.scope)
+			if tt.errorContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errorContains) {
+					t.Errorf("Expected error containing '%s', got '%v'", tt.errorContains, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if s.Type() != tt.wantType || s.Count() != tt.wantCount {
+				t.Errorf("ParseAccountScope() = %s count %d, want %s count %d", s.Type(), s.Count(), tt.wantType, tt.wantCount)
+			}
+		})
+	}
+}
+
+// TestProcessorValidateAccount tests validating conditions against every character an account has in a world
+func TestProcessorValidateAccount(t *testing.T) {
+	characters := []character.Model{
+		character.NewModelBuilder().SetId(1).SetAccountId(7).SetLevel(120).Build(),
+		character.NewModelBuilder().SetId(2).SetAccountId(7).SetLevel(30).Build(),
+		character.NewModelBuilder().SetId(3).SetAccountId(7).SetLevel(75).Build(),
+	}
+	conditions := []ConditionInput{{Type: "level", Operator: ">=", Value: 70}, {Type: "item", Operator: ">=", Value: 0, ReferenceId: 2000001}}
+
+	tests := []struct {
+		name            string
+		characters      []character.Model
+		scope           string
+		wantPassed      bool
+		wantPassedCount int
+	}{
+		{name: "All characters", characters: characters, scope: "all", wantPassed: false, wantPassedCount: 2},
+		{name: "Any character", characters: characters, scope: "any", wantPassed: true, wantPassedCount: 2},
+		{name: "Count met", characters: characters, scope: "count:2", wantPassed: true, wantPassedCount: 2},
+		{name: "Count not met", characters: characters, scope: "count:3", wantPassed: false, wantPassedCount: 2},
+		{name: "No characters", characters: []character.Model{}, scope: "all", wantPassed: false, wantPassedCount: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var inventoryFetches []uint32
+			mockCharProcessor := &mock.ProcessorImpl{
+				GetByAccountAndWorldFunc: func(decorators ...model.Decorator[character.Model]) func(accountId uint32, worldId world.Id) ([]character.Model, error) {
+					return func(accountId uint32, worldId world.Id) ([]character.Model, error) {
+						if accountId != 7 || worldId != 1 {
+							t.Errorf("Expected account 7 in world 1, got account %d in world %d", accountId, worldId)
+						}
+						return tt.characters, nil
+					}
+				},
+				WithInventoryFunc: func(m character.Model) (character.Model, error) {
+					inventoryFetches = append(inventoryFetches, m.Id())
+					return m, nil
+				},
+			}
+			processor := &ProcessorImpl{
+				l:                  logrus.New(),
+				ctx:                context.Background(),
+				characterProcessor: mockCharProcessor,
+			}
+
+			scope, err := ParseAccountScope(tt.scope)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			result, err := processor.ValidateAccount(ModeDecorator(Mode{modeType: AllMode}))(7, 1, scope, conditions)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if result.Passed() != tt.wantPassed || result.PassedCount() != tt.wantPassedCount || result.TotalCount() != len(tt.characters) {
+				t.Errorf("ValidateAccount() passed %v, %d of %d, want passed %v, %d of %d", result.Passed(), result.PassedCount(), result.TotalCount(), tt.wantPassed, tt.wantPassedCount, len(tt.characters))
+			}
+			if len(inventoryFetches) != len(tt.characters) {
+				t.Errorf("Expected the inventory of each character to be fetched, got %v", inventoryFetches)
+			}
+			for i, c := range result.Characters() {
+				if c.CharacterId() != tt.characters[i].Id() || c.TotalCount() != 2 {
+					t.Errorf("Character %d result = %+v", i, c)
+				}
+			}
+		})
+	}
+
+	t.Run("Account fetch failure", func(t *testing.T) {
+		processor := &ProcessorImpl{
+			l:   logrus.New(),
+			ctx: context.Background(),
+			characterProcessor: &mock.ProcessorImpl{
+				GetByAccountAndWorldFunc: func(decorators ...model.Decorator[character.Model]) func(accountId uint32, worldId world.Id) ([]character.Model, error) {
+					return func(accountId uint32, worldId world.Id) ([]character.Model, error) {
+						return nil, errors.New("service unavailable")
+					}
+				},
+			},
+		}
+		if _, err := processor.ValidateAccount()(7, 1, AccountScope{scopeType: AllScope}, conditions); err == nil || !strings.Contains(err.Error(), "failed to get account characters") {
+			t.Errorf("Expected an account fetch error, got %v", err)
+		}
+	})
+
+	t.Run("Account fetch duration", func(t *testing.T) {
+		processor := &ProcessorImpl{
+			l:   logrus.New(),
+			ctx: context.Background(),
+			characterProcessor: &mock.ProcessorImpl{
+				GetByAccountAndWorldFunc: func(decorators ...model.Decorator[character.Model]) func(accountId uint32, worldId world.Id) ([]character.Model, error) {
+					return func(accountId uint32, worldId world.Id) ([]character.Model, error) {
+						time.Sleep(20 * time.Millisecond)
+						return characters[:2], nil
+					}
+				},
+			},
+		}
+		result, err := processor.ValidateAccount(Explain)(7, 1, AccountScope{scopeType: AllScope}, []ConditionInput{{Type: "level", Operator: ">=", Value: 70}})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for _, c := range result.Characters() {
+			explanation := c.Results()[0].Explanation
+			if explanation == nil || explanation.Source != CharactersSource || explanation.DurationMs < 20 {
+				t.Errorf("Expected character %d to report the account fetch duration, got %+v", c.CharacterId(), explanation)
+			}
+		}
+	})
+}
+
+// TestTransformAccount tests that each character result is included with its character ID
+func TestTransformAccount(t *testing.T) {
+	passing := NewValidationResult(1)
+	passing.AddConditionResult(ConditionResult{Passed: true, Description: "Level >= 70"})
+	failing := NewValidationResult(2)
+	failing.AddConditionResult(ConditionResult{Passed: false, Description: "Level >= 70"})
+	failing = ModeDecorator(Mode{modeType: AllMode})(failing)
+
+	rm, err := TransformAccount(NewAccountValidationResult(7, AccountScope{scopeType: AnyScope}, []ValidationResult{passing, failing}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rm.GetID() != "7" || rm.Scope != "any" || !rm.Passed || rm.PassedCount != 1 || rm.TotalCount != 2 {
+		t.Errorf("TransformAccount() = %+v", rm)
+	}
+	if len(rm.Characters) != 2 || rm.Characters[0].CharacterId != 1 || !rm.Characters[0].Passed || rm.Characters[1].CharacterId != 2 || rm.Characters[1].Passed {
+		t.Errorf("Characters = %+v", rm.Characters)
+	}
+}
@@ -0,0 +1,193 @@
+package validation
+
+import (
+	"fmt"
+	"slices"
+)
+
+// CompileError reports a problem with a condition, or with the request when Index is nil
+type CompileError struct {
+	Index   *int   `json:"index,omitempty"` // Index of the top-level condition the error belongs to
+	Path    string `json:"path,omitempty"`  // Location of the failing node, e.g. conditions[2].all[1]
+	Message string `json:"message"`
+}
+
+// Compilation is the outcome of compiling conditions without evaluating them
+type Compilation struct {
+	conditions []Condition
+	errors     []CompileError
+	sources    []DataSource
+}
+
+// Valid returns whether every condition compiled without error
+func (c Compilation) Valid() bool {
+	return len(c.errors) == 0
+}
+
+// Conditions returns the conditions that compiled, in request order
+func (c Compilation) Conditions() []Condition {
+	return c.conditions
+}
+
+// Errors returns every error found, in request order
+func (c Compilation) Errors() []CompileError {
+	return c.errors
+}
+
+// DataSources returns the upstream resources evaluating the compiled conditions would fetch
+func (c Compilation) DataSources() []DataSource {
+	return c.sources
+}
+
+// dataSources lists every data source in the order they are reported
+var dataSources = []DataSource{CharactersSource, InventorySource, GuildsSource, QuestsSource, MarriageSource, ConfigurationSource}
+
+// Compile runs the mode and each condition input through parameter binding, input validation and the condition builder
+// without fetching any data. Every node of every condition is checked, so the result reports each problem found rather
+// than the first.
+func Compile(parameters Parameters, mode string, threshold *int, conditionInputs []ConditionInput) Compilation {
+	result := Compilation{
+		conditions: make([]Condition, 0, len(conditionInputs)),
+		errors:     make([]CompileError, 0),
+		sources:    make([]DataSource, 0),
+	}
+
+	if len(conditionInputs) == 0 {
+		result.errors = append(result.errors, CompileError{Message: "at least one condition is required"})
+	}
+	if m, err := ParseMode(mode, threshold); err != nil {
+		result.errors = append(result.errors, CompileError{Message: err.Error()})
+	} else if err := m.validateFor(len(conditionInputs)); err != nil {
+		result.errors = append(result.errors, CompileError{Message: err.Error()})
+	}
+
+	for i, input := range conditionInputs {
+		index := i
+		path := fmt.Sprintf("conditions[%d]", i)
+		problems := compileProblems(parameters, path, input)
+		if len(problems) == 0 {
+			condition, err := compileCondition(parameters, input)
+			if err == nil {
+				result.conditions = append(result.conditions, condition)
+				continue
+			}
+			problems = []CompileError{{Path: path, Message: err.Error()}}
+		}
+		for _, problem := range problems {
+			problem.Index = &index
+			result.errors = append(result.errors, problem)
+		}
+	}
+
+	if len(result.conditions) > 0 {
+		result.sources = fetchPlan(result.conditions...)
+	}
+	return result
+}
+
+// compileProblems walks a condition input tree, binding and validating every node, and returns each problem found
+// with the path of the node it belongs to
+func compileProblems(parameters Parameters, path string, input ConditionInput) []CompileError {
+	errs := make([]CompileError, 0)
+	report := func(err error) {
+		errs = append(errs, CompileError{Path: path, Message: err.Error()})
+	}
+
+	// Expression nodes are checked in their parsed form, which carries the weight of the expression
+	if input.Expression != "" {
+		parsed, err := input.resolveExpression()
+		if err != nil {
+			report(err)
+			return errs
+		}
+		return compileProblems(parameters, path, parsed)
+	}
+
+	if input.Weight != nil && *input.Weight < 1 {
+		report(fmt.Errorf("weight must be positive"))
+	}
+
+	// Group nodes are checked by checking every member
+	if input.IsGroup() {
+		group, members, err := input.groupMembers()
+		if err != nil {
+			report(err)
+			return errs
+		}
+		for i, member := range members {
+			errs = append(errs, compileProblems(parameters, fmt.Sprintf("%s.%s[%d]", path, group, i), member)...)
+		}
+		return errs
+	}
+
+	// Malformed placeholders cannot be bound, so the unbound input is checked instead
+	if err := input.validatePlaceholders(); err != nil {
+		for _, problem := range conditionProblems(input) {
+			report(problem)
+		}
+		return errs
+	}
+	bound, err := input.bind(parameters)
+	if err != nil {
+		report(err)
+		bound = input
+	}
+	for _, problem := range conditionProblems(bound) {
+		report(problem)
+	}
+	return errs
+}
+
+// compileCondition binds, validates and builds a single condition input
+func compileCondition(parameters Parameters, input ConditionInput) (Condition, error) {
+	bound, err := BindParameters(parameters)(input)
+	if err != nil {
+		return Condition{}, err
+	}
+	if err := validateConditionInput(bound); err != nil {
+		return Condition{}, err
+	}
+	return NewConditionBuilder().FromInput(bound).Build()
+}
+
+// Input returns the normalized structured form of the condition. Expressions appear as the groups and comparisons
+// they compile to, deprecated item IDs as reference IDs, and only the operands used by the operator are included.
+func (c Condition) Input() ConditionInput {
+	var input ConditionInput
+	if c.weight != 0 {
+		weight := c.weight
+		input.Weight = &weight
+	}
+
+	if c.group != "" {
+		members := make([]ConditionInput, 0, len(c.children))
+		for _, child := range c.children {
+			members = append(members, child.Input())
+		}
+		switch c.group {
+		case AllGroup:
+			input.All = members
+		case AnyGroup:
+			input.Any = members
+		case NotGroup:
+			input.Not = &members[0]
+		}
+		return input
+	}
+
+	input.Type = string(c.conditionType)
+	input.Operator = string(c.operator)
+	input.ReferenceId = c.referenceId
+	input.Step = c.step
+	switch {
+	case c.operator.IsSet():
+		input.Values = slices.Clone(c.values)
+	case c.operator == Between:
+		min, max := c.min, c.max
+		input.Min = &min
+		input.Max = &max
+	default:
+		input.Value = c.value
+	}
+	return input
+}
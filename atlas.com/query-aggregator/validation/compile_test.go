@@ -0,0 +1,151 @@
+package validation
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestCompile tests compiling conditions without evaluating them
+func TestCompile(t *testing.T) {
+	conditions := []ConditionInput{
+		{Expression: "level>=30 && item[2000001]>=$count"},
+		{Type: "guildRank", Operator: "<=", Value: 9},
+		{Type: "item", Operator: ">=", Value: 1, ItemId: 4001000},
+		{Type: "unknown", Operator: "=", Value: 1},
+		{Type: "questStatus", Operator: "=", Value: 2, ReferenceId: 1001},
+	}
+
+	c := Compile(Parameters{"count": float64(10)}, "", nil, conditions)
+	if c.Valid() {
+		t.Fatalf("Expected compilation to be invalid")
+	}
+
+	wantErrors := map[int]string{
+		1: "guild rank value must be between 0 and 5",
+		3: "unsupported condition type: unknown",
+	}
+	if len(c.Errors()) != len(wantErrors) {
+		t.Fatalf("Expected %d errors, got %+v", len(wantErrors), c.Errors())
+	}
+	for _, e := range c.Errors() {
+		if e.Index == nil {
+			t.Fatalf("Expected error to carry its condition index, got %+v", e)
+		}
+		if want, ok := wantErrors[*e.Index]; !ok || !strings.Contains(e.Message, want) {
+			t.Errorf("Unexpected error for condition %d: %s", *e.Index, e.Message)
+		}
+	}
+
+	wantConditions := []ConditionInput{
+		{All: []ConditionInput{
+			{Type: "level", Operator: ">=", Value: 30},
+			{Type: "item", Operator: ">=", Value: 10, ReferenceId: 2000001},
+		}},
+		{Type: "item", Operator: ">=", Value: 1, ReferenceId: 4001000},
+		{Type: "questStatus", Operator: "=", Value: 2, ReferenceId: 1001},
+	}
+	normalized := make([]ConditionInput, 0, len(c.Conditions()))
+	for _, condition := range c.Conditions() {
+		normalized = append(normalized, condition.Input())
+	}
+	if !reflect.DeepEqual(normalized, wantConditions) {
+		t.Errorf("Normalized conditions = %+v, want %+v", normalized, wantConditions)
+	}
+
+	wantSources := []DataSource{CharactersSource, InventorySource, QuestsSource}
+	if !reflect.DeepEqual(c.DataSources(), wantSources) {
+		t.Errorf("DataSources() = %v, want %v", c.DataSources(), wantSources)
+	}
+}
+
+// TestCompile_CollectsEveryError tests that every problem in a condition and in the members of a group is reported with
+// its path
+func TestCompile_CollectsEveryError(t *testing.T) {
+	weight := 0
+	conditions := []ConditionInput{
+		{Type: "level", Operator: ">=", Value: 30},
+		{Type: "guildRank", Operator: "<=", Value: 9, ItemId: 1, ReferenceId: 2, Weight: &weight},
+		{All: []ConditionInput{
+			{Type: "level", Operator: ">=", Value: 30},
+			{Type: "unknown", Operator: "~"},
+			{Any: []ConditionInput{
+				{Type: "questStatus", Operator: "=", Value: 7},
+				{Type: "item", Operator: ">=", ValueParam: "missing", ReferenceId: 2000001},
+			}},
+		}},
+	}
+
+	c := Compile(Parameters{}, "", nil, conditions)
+
+	type located struct {
+		index int
+		path  string
+		error string
+	}
+	want := []located{
+		{1, "conditions[1]", "weight must be positive"},
+		{1, "conditions[1]", "both itemId and referenceId specified"},
+		{1, "conditions[1]", "guild rank value must be between 0 and 5"},
+		{2, "conditions[2].all[1]", "unsupported condition type: unknown"},
+		{2, "conditions[2].all[1]", "unsupported operator: ~"},
+		{2, "conditions[2].all[2].any[0]", "referenceId is required for quest"},
+		{2, "conditions[2].all[2].any[0]", "quest status value must be between"},
+		{2, "conditions[2].all[2].any[1]", "missing"},
+	}
+	if len(c.Errors()) != len(want) {
+		t.Fatalf("Expected %d errors, got %+v", len(want), c.Errors())
+	}
+	for i, e := range c.Errors() {
+		if e.Index == nil || *e.Index != want[i].index || e.Path != want[i].path || !strings.Contains(e.Message, want[i].error) {
+			t.Errorf("Error %d = %+v, want %+v", i, e, want[i])
+		}
+	}
+	if len(c.Conditions()) != 1 {
+		t.Errorf("Expected only the valid condition to compile, got %d", len(c.Conditions()))
+	}
+}
+
+// TestCompile_RequestErrors tests errors that do not belong to a single condition
+func TestCompile_RequestErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		mode          string
+		conditions    []ConditionInput
+		errorContains string
+	}{
+		{name: "No conditions", errorContains: "at least one condition is required"},
+		{name: "Invalid mode", mode: "most", conditions: []ConditionInput{{Type: "level", Operator: ">=", Value: 1}}, errorContains: "unsupported mode: most"},
+		{name: "Unsatisfiable quorum", mode: "atLeast:2", conditions: []ConditionInput{{Type: "level", Operator: ">=", Value: 1}}, errorContains: "requires more conditions than the 1 provided"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Compile(nil, tt.mode, nil, tt.conditions)
+			if c.Valid() || c.Errors()[0].Index != nil || !strings.Contains(c.Errors()[0].Message, tt.errorContains) {
+				t.Errorf("Expected request error containing '%s', got %+v", tt.errorContains, c.Errors())
+			}
+		})
+	}
+}
+
+// TestCondition_Input tests that normalized inputs build the same condition
+func TestCondition_Input(t *testing.T) {
+	weight := 2
+	inputs := []ConditionInput{
+		{Type: "jobId", Operator: "in", Values: []int{100, 110}},
+		{Type: "level", Operator: "between", Min: intPtr(30), Max: intPtr(50)},
+		{Type: "questProgress", Operator: ">=", Value: 5, ReferenceId: 1001, Step: "mobsKilled"},
+		{Any: []ConditionInput{{Type: "fame", Operator: ">", Value: 0}, {Not: &ConditionInput{Type: "gender", Operator: "=", Value: 1}}}, Weight: &weight},
+	}
+
+	for _, input := range inputs {
+		condition, err := NewConditionBuilder().FromInput(input).Build()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(condition.Input(), input) {
+			t.Errorf("Input() = %+v, want %+v", condition.Input(), input)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package validation
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TenantConfiguration holds the tenant configured data conditions are evaluated against
+type TenantConfiguration struct {
+	mapGroups map[string][]uint32
+	location  *time.Location
+}
+
+// NewTenantConfiguration creates a tenant configuration from the named map groups of the tenant and the timezone time
+// conditions are evaluated in
+func NewTenantConfiguration(mapGroups map[string][]uint32, location *time.Location) TenantConfiguration {
+	return TenantConfiguration{mapGroups: mapGroups, location: location}
+}
+
+// Location returns the timezone time conditions are evaluated in, UTC when the tenant has not configured one
+func (c TenantConfiguration) Location() *time.Location {
+	if c.location == nil {
+		return time.UTC
+	}
+	return c.location
+}
+
+// MapGroup returns the maps of a named group, and whether the group is configured
+func (c TenantConfiguration) MapGroup(name string) ([]uint32, bool) {
+	maps, ok := c.mapGroups[name]
+	return maps, ok
+}
+
+// ConfigurationResolver resolves the configuration of the requesting tenant
+type ConfigurationResolver func() (TenantConfiguration, error)
+
+// ConfigurationResolverProvider creates a ConfigurationResolver scoped to a request
+type ConfigurationResolverProvider func(l logrus.FieldLogger, ctx context.Context) ConfigurationResolver
@@ -6,13 +6,19 @@ import (
 	"atlas-query-aggregator/quest"
 	"fmt"
 	"github.com/Chronicle20/atlas-model/model"
+	"slices"
+	"time"
 )
 
 // ValidationContext provides all the data needed for validation
 type ValidationContext struct {
-	character character.Model
-	quests    map[uint32]quest.Model
-	marriage  marriage.Model
+	character     character.Model
+	quests        map[uint32]quest.Model
+	marriage      marriage.Model
+	configuration TenantConfiguration
+	withheld      []DataSource // Sources the context was created without, whose conditions cannot be evaluated
+	unavailable   []DataSource // Sources whose fetch failed, whose conditions fail as data unavailable
+	now           time.Time    // Server time the validation is evaluated at
 }
 
 // NewValidationContext creates a new validation context with the provided character
@@ -21,20 +27,88 @@ func NewValidationContext(char character.Model) ValidationContext {
 		character: char,
 		quests:    make(map[uint32]quest.Model),
 		marriage:  marriage.NewModel(char.Id(), false),
+		now:       time.Now(),
 	}
 }
 
+// characterContext wraps a character in a validation context supplying no quest or marriage data
+func characterContext(char character.Model) ValidationContext {
+	ctx := NewValidationContext(char)
+	ctx.withheld = []DataSource{QuestsSource, MarriageSource}
+	return ctx
+}
+
+// Supplies returns whether the context holds data from the source. Contexts created for a character alone supply
+// neither quests nor marriage data until they are added.
+func (ctx ValidationContext) Supplies(source DataSource) bool {
+	return !slices.Contains(ctx.withheld, source)
+}
+
+// withUnavailable withholds a source whose fetch failed, so the conditions reading it fail as data unavailable
+func (ctx ValidationContext) withUnavailable(source DataSource) ValidationContext {
+	if ctx.Supplies(source) {
+		ctx.withheld = append(slices.Clone(ctx.withheld), source)
+	}
+	ctx.unavailable = append(slices.Clone(ctx.unavailable), source)
+	return ctx
+}
+
+// unavailableSource returns the first of the sources whose fetch failed, if any
+func (ctx ValidationContext) unavailableSource(sources []DataSource) (DataSource, bool) {
+	for _, source := range sources {
+		if slices.Contains(ctx.unavailable, source) {
+			return source, true
+		}
+	}
+	return "", false
+}
+
+// supply returns the sources withheld from the context other than the source
+func (ctx ValidationContext) supply(source DataSource) []DataSource {
+	return slices.DeleteFunc(slices.Clone(ctx.withheld), func(s DataSource) bool { return s == source })
+}
+
 // Character returns the character model
 func (ctx ValidationContext) Character() character.Model {
 	return ctx.character
 }
 
+// Now returns the server time the validation is evaluated at, used to disregard expired items
+func (ctx ValidationContext) Now() time.Time {
+	if ctx.now.IsZero() {
+		return time.Now()
+	}
+	return ctx.now
+}
+
+// WithNow sets the server time the validation is evaluated at
+func (ctx ValidationContext) WithNow(now time.Time) ValidationContext {
+	ctx.now = now
+	return ctx
+}
+
+// Configuration returns the configuration of the tenant the validation is evaluated for
+func (ctx ValidationContext) Configuration() TenantConfiguration {
+	return ctx.configuration
+}
+
+// WithConfiguration sets the configuration of the tenant the validation is evaluated for
+func (ctx ValidationContext) WithConfiguration(configuration TenantConfiguration) ValidationContext {
+	ctx.configuration = configuration
+	return ctx
+}
+
 // Quest returns the quest model for the given quest ID
 func (ctx ValidationContext) Quest(questId uint32) (quest.Model, bool) {
 	q, exists := ctx.quests[questId]
 	return q, exists
 }
 
+// Quests returns every quest in the context, keyed by quest ID
+func (ctx ValidationContext) Quests() map[uint32]quest.Model {
+	return ctx.quests
+}
+
 // Marriage returns the marriage model
 func (ctx ValidationContext) Marriage() marriage.Model {
 	return ctx.marriage
@@ -47,28 +121,33 @@ func (ctx ValidationContext) WithQuest(questModel quest.Model) ValidationContext
 		newQuests[k] = v
 	}
 	newQuests[questModel.Id()] = questModel
-	
-	return ValidationContext{
-		character: ctx.character,
-		quests:    newQuests,
-		marriage:  ctx.marriage,
-	}
+
+	ctx.quests = newQuests
+	ctx.withheld = ctx.supply(QuestsSource)
+	return ctx
+}
+
+// WithQuests replaces the quests of the context with every quest of the character, keyed by quest ID
+func (ctx ValidationContext) WithQuests(quests map[uint32]quest.Model) ValidationContext {
+	ctx.quests = quests
+	ctx.withheld = ctx.supply(QuestsSource)
+	return ctx
 }
 
 // WithMarriage adds marriage data to the context
 func (ctx ValidationContext) WithMarriage(marriageModel marriage.Model) ValidationContext {
-	return ValidationContext{
-		character: ctx.character,
-		quests:    ctx.quests,
-		marriage:  marriageModel,
-	}
+	ctx.marriage = marriageModel
+	ctx.withheld = ctx.supply(MarriageSource)
+	return ctx
 }
 
 // ValidationContextBuilder provides a builder pattern for creating validation contexts
 type ValidationContextBuilder struct {
-	character character.Model
-	quests    map[uint32]quest.Model
-	marriage  marriage.Model
+	character     character.Model
+	quests        map[uint32]quest.Model
+	marriage      marriage.Model
+	configuration TenantConfiguration
+	now           time.Time
 }
 
 // NewValidationContextBuilder creates a new validation context builder
@@ -77,6 +156,7 @@ func NewValidationContextBuilder(char character.Model) *ValidationContextBuilder
 		character: char,
 		quests:    make(map[uint32]quest.Model),
 		marriage:  marriage.NewModel(char.Id(), false),
+		now:       time.Now(),
 	}
 }
 
@@ -95,12 +175,26 @@ func (b *ValidationContextBuilder) SetMarriage(marriageModel marriage.Model) *Va
 	return b
 }
 
+// SetConfiguration sets the configuration of the tenant for the context being built
+func (b *ValidationContextBuilder) SetConfiguration(configuration TenantConfiguration) *ValidationContextBuilder {
+	b.configuration = configuration
+	return b
+}
+
+// SetNow sets the server time the validation is evaluated at
+func (b *ValidationContextBuilder) SetNow(now time.Time) *ValidationContextBuilder {
+	b.now = now
+	return b
+}
+
 // Build creates a validation context from the builder
 func (b *ValidationContextBuilder) Build() ValidationContext {
 	return ValidationContext{
-		character: b.character,
-		quests:    b.quests,
-		marriage:  b.marriage,
+		character:     b.character,
+		quests:        b.quests,
+		marriage:      b.marriage,
+		configuration: b.configuration,
+		now:           b.now,
 	}
 }
 
@@ -110,6 +204,14 @@ type ValidationContextProvider interface {
 	GetValidationContext(characterId uint32) model.Provider[ValidationContext]
 }
 
+// contextProviderFunc adapts a function into a ValidationContextProvider
+type contextProviderFunc func(characterId uint32) model.Provider[ValidationContext]
+
+// GetValidationContext returns a provider that builds a validation context for the given character
+func (f contextProviderFunc) GetValidationContext(characterId uint32) model.Provider[ValidationContext] {
+	return f(characterId)
+}
+
 // ContextBuilderProvider provides a way to create validation contexts with data from multiple services
 type ContextBuilderProvider struct {
 	characterProvider func(uint32) model.Provider[character.Model]
@@ -164,4 +266,4 @@ func (p *ContextBuilderProvider) GetValidationContext(characterId uint32) model.
 
 		return builder.Build(), nil
 	}
-}
\ No newline at end of file
+}
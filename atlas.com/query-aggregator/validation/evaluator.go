@@ -0,0 +1,412 @@
+package validation
+
+import (
+	"atlas-query-aggregator/character"
+	"atlas-query-aggregator/guild"
+	"atlas-query-aggregator/quest"
+	"fmt"
+	"time"
+
+	inventory2 "github.com/Chronicle20/atlas-constants/inventory"
+	"github.com/Chronicle20/atlas-constants/inventory/slot"
+	"github.com/Chronicle20/atlas-constants/item"
+	"github.com/Chronicle20/atlas-constants/job"
+)
+
+// Evaluation is the actual value of a non-group condition, as determined by its evaluator
+type Evaluation struct {
+	ActualValue int
+	Description string
+	ItemId      uint32         // Echoed in the result of item conditions
+	Breakdown   *StatBreakdown // Composition of the actual value of total statistic conditions
+	ServerTime  *time.Time     // Server time, in the tenant timezone, the actual value of time conditions was read from
+	Values      []int          // Values a named list expanded to, compared against in place of the condition values
+	Unavailable bool           // The actual value could not be determined, so the condition fails without comparison
+}
+
+// Evaluator determines the actual value of conditions of a single type from the data fetched for a validation
+type Evaluator interface {
+	// Needs returns the data sources the evaluator reads. The processor fetches the union of the sources needed by
+	// every condition being validated, and nothing else.
+	Needs() []DataSource
+
+	// Evaluate determines the actual value of the condition from the validation context
+	Evaluate(c Condition, ctx ValidationContext) Evaluation
+}
+
+// evaluatorFunc adapts a function and the data sources it reads into an Evaluator
+type evaluatorFunc struct {
+	needs    []DataSource
+	evaluate func(c Condition, ctx ValidationContext) Evaluation
+}
+
+// NewEvaluator creates an Evaluator from a function reading the declared data sources
+func NewEvaluator(evaluate func(c Condition, ctx ValidationContext) Evaluation, needs ...DataSource) Evaluator {
+	return evaluatorFunc{needs: needs, evaluate: evaluate}
+}
+
+func (e evaluatorFunc) Needs() []DataSource {
+	return e.needs
+}
+
+func (e evaluatorFunc) Evaluate(c Condition, ctx ValidationContext) Evaluation {
+	return e.evaluate(c, ctx)
+}
+
+// needs returns the data sources read by every non-group condition in the condition tree
+func (c Condition) needs() []DataSource {
+	var result []DataSource
+	for _, leaf := range c.leaves() {
+		if definition, err := LookupConditionType(string(leaf.conditionType)); err == nil {
+			result = append(result, definition.sourcesFor(leaf.step)...)
+		}
+	}
+	return result
+}
+
+// fetchPlan returns the data sources evaluating the conditions reads, in reporting order. The character is always
+// included, as every other source decorates or is keyed by it.
+func fetchPlan(conditions ...Condition) []DataSource {
+	needed := map[DataSource]bool{CharactersSource: true}
+	for _, condition := range conditions {
+		for _, source := range condition.needs() {
+			needed[source] = true
+		}
+	}
+
+	plan := make([]DataSource, 0, len(needed))
+	for _, source := range dataSources {
+		if needed[source] {
+			plan = append(plan, source)
+		}
+	}
+	return plan
+}
+
+// characterValue creates an evaluator comparing a value read from the character model
+func characterValue(label string, value func(character.Model) int) Evaluator {
+	return NewEvaluator(func(c Condition, ctx ValidationContext) Evaluation {
+		return Evaluation{
+			ActualValue: value(ctx.Character()),
+			Description: fmt.Sprintf("%s %s", label, c.Expectation()),
+		}
+	}, CharactersSource)
+}
+
+// jobBranchEvaluator yields 1 when the character's job is the referenced job or was reached by advancing from it, and
+// 0 otherwise
+var jobBranchEvaluator = NewEvaluator(func(c Condition, ctx ValidationContext) Evaluation {
+	actualValue := 0
+	if ctx.Character().JobDescendsFrom(job.Id(c.referenceId)) {
+		actualValue = 1
+	}
+	return Evaluation{ActualValue: actualValue, Description: fmt.Sprintf("Job Branch %d %s", c.referenceId, c.Expectation())}
+}, CharactersSource)
+
+// mapInEvaluator yields the character's map, compared against the listed maps or the maps of the tenant configured map
+// group named by the step. A group the tenant has not configured cannot be checked.
+var mapInEvaluator = NewEvaluator(func(c Condition, ctx ValidationContext) Evaluation {
+	actualValue := int(ctx.Character().MapId())
+	if c.step == "" {
+		return Evaluation{ActualValue: actualValue, Description: fmt.Sprintf("Map %s", c.Expectation())}
+	}
+
+	maps, ok := ctx.Configuration().MapGroup(c.step)
+	if !ok {
+		return Evaluation{ActualValue: actualValue, Description: fmt.Sprintf("Map %s %s (no such map group)", c.operator, c.step), Unavailable: true}
+	}
+	values := make([]int, 0, len(maps))
+	for _, m := range maps {
+		values = append(values, int(m))
+	}
+	return Evaluation{ActualValue: actualValue, Description: fmt.Sprintf("Map %s %s", c.operator, c.step), Values: values}
+}, CharactersSource)
+
+// serverTimeValue creates an evaluator yielding a value of the server time, read in the timezone of the tenant
+func serverTimeValue(label string, f func(now time.Time) int) Evaluator {
+	return NewEvaluator(func(c Condition, ctx ValidationContext) Evaluation {
+		now := ctx.Now().In(ctx.Configuration().Location())
+		return Evaluation{ActualValue: f(now), Description: fmt.Sprintf("%s %s", label, c.Expectation()), ServerTime: &now}
+	}, ConfigurationSource)
+}
+
+// dateOf returns the calendar date of the time as a YYYYMMDD number, e.g. 20261031
+func dateOf(t time.Time) int {
+	return t.Year()*10000 + int(t.Month())*100 + t.Day()
+}
+
+// monthDayOf returns the day of the year of the time as a MMDD number, e.g. 1031, recurring every year
+func monthDayOf(t time.Time) int {
+	return int(t.Month())*100 + t.Day()
+}
+
+// guildDescription notes when the character is not in a guild
+func guildDescription(m character.Model, description string) string {
+	if m.Guild().Id() == 0 {
+		return fmt.Sprintf("%s (character not in guild)", description)
+	}
+	return description
+}
+
+// guildValue creates an evaluator comparing a value read from the guild of the character. The description notes
+// when the character is not in a guild.
+func guildValue(label string, value func(character.Model) int) Evaluator {
+	return NewEvaluator(func(c Condition, ctx ValidationContext) Evaluation {
+		m := ctx.Character()
+		description := guildDescription(m, fmt.Sprintf("%s %s", label, c.Expectation()))
+		return Evaluation{ActualValue: value(m), Description: description}
+	}, CharactersSource, GuildsSource)
+}
+
+// guildMembers creates an evaluator counting the members of the character's guild at or above the threshold given as
+// the referenceId, e.g. "Guild Members at or above Level 70 >= 5"
+func guildMembers(label string, count func(g guild.Model, threshold byte) int) Evaluator {
+	return NewEvaluator(func(c Condition, ctx ValidationContext) Evaluation {
+		m := ctx.Character()
+		description := guildDescription(m, fmt.Sprintf("Guild Members at or above %s %d %s", label, c.referenceId, c.Expectation()))
+		return Evaluation{ActualValue: count(m.Guild(), byte(c.referenceId)), Description: description}
+	}, CharactersSource, GuildsSource)
+}
+
+// guildLeaderEvaluator yields 1 when the character leads its guild and 0 otherwise
+var guildLeaderEvaluator = NewEvaluator(func(c Condition, ctx ValidationContext) Evaluation {
+	m := ctx.Character()
+	actualValue := 0
+	if m.Guild().Id() != 0 && m.Guild().LeaderId() == m.Id() {
+		actualValue = 1
+	}
+	return Evaluation{ActualValue: actualValue, Description: fmt.Sprintf("Guild Leader %s", c.Expectation())}
+}, CharactersSource, GuildsSource)
+
+// expired returns whether an expiration has passed at the server time. A zero expiration never expires.
+func expired(expiration time.Time, now time.Time) bool {
+	return !expiration.IsZero() && !expiration.After(now)
+}
+
+// itemQuantity returns the quantity of the unexpired item held across the compartment the item belongs to, and
+// whether the item ID identifies a compartment. Equipped items are not held in a compartment.
+func itemQuantity(m character.Model, templateId uint32, now time.Time) (int, bool) {
+	it, ok := inventory2.TypeFromItemId(item.Id(templateId))
+	if !ok {
+		return 0, false
+	}
+
+	quantity := 0
+	for _, a := range m.Inventory().CompartmentByType(it).Assets() {
+		if a.TemplateId() == templateId && !expired(a.Expiration(), now) {
+			quantity += int(a.Quantity())
+		}
+	}
+	return quantity, true
+}
+
+// equippedExpirations returns the expiration of each copy of the item worn in the equipment slots, regular or cash.
+// An empty slot type includes every slot.
+func equippedExpirations(m character.Model, templateId uint32, slotType slot.Type) []time.Time {
+	var result []time.Time
+	for t, s := range m.Equipment().Slots() {
+		if slotType != "" && t != slotType {
+			continue
+		}
+		if s.Equipable != nil && s.Equipable.TemplateId() == templateId {
+			result = append(result, s.Equipable.Expiration())
+		}
+		if s.CashEquipable != nil && s.CashEquipable.TemplateId() == templateId {
+			result = append(result, s.CashEquipable.Expiration())
+		}
+	}
+	return result
+}
+
+// equippedCount returns the number of equipment slots, regular or cash, holding an unexpired copy of the item. An
+// empty slot type counts every slot.
+func equippedCount(m character.Model, templateId uint32, slotType slot.Type, now time.Time) int {
+	count := 0
+	for _, expiration := range equippedExpirations(m, templateId, slotType) {
+		if !expired(expiration, now) {
+			count++
+		}
+	}
+	return count
+}
+
+// itemEvaluator yields the quantity of the referenced item held across the compartment the item belongs to
+var itemEvaluator = NewEvaluator(func(c Condition, ctx ValidationContext) Evaluation {
+	quantity, ok := itemQuantity(ctx.Character(), c.referenceId, ctx.Now())
+	if !ok {
+		return Evaluation{Description: fmt.Sprintf("Invalid item ID: %d", c.referenceId), ItemId: c.referenceId, Unavailable: true}
+	}
+	return Evaluation{
+		ActualValue: quantity,
+		Description: fmt.Sprintf("Item %d quantity %s", c.referenceId, c.Expectation()),
+		ItemId:      c.referenceId,
+	}
+}, CharactersSource, InventorySource)
+
+// equippedEvaluator yields the number of slots wearing the referenced item, narrowed to the slot type named by the
+// step when one is given
+var equippedEvaluator = NewEvaluator(func(c Condition, ctx ValidationContext) Evaluation {
+	description := fmt.Sprintf("Item %d equipped %s", c.referenceId, c.Expectation())
+	if c.step != "" {
+		description = fmt.Sprintf("Item %d equipped (slot: %s) %s", c.referenceId, c.step, c.Expectation())
+	}
+	return Evaluation{
+		ActualValue: equippedCount(ctx.Character(), c.referenceId, slot.Type(c.step), ctx.Now()),
+		Description: description,
+		ItemId:      c.referenceId,
+	}
+}, CharactersSource, InventorySource)
+
+// itemOwnedEvaluator yields the quantity of the referenced item held in the inventory or worn
+var itemOwnedEvaluator = NewEvaluator(func(c Condition, ctx ValidationContext) Evaluation {
+	quantity, ok := itemQuantity(ctx.Character(), c.referenceId, ctx.Now())
+	if !ok {
+		return Evaluation{Description: fmt.Sprintf("Invalid item ID: %d", c.referenceId), ItemId: c.referenceId, Unavailable: true}
+	}
+	return Evaluation{
+		ActualValue: quantity + equippedCount(ctx.Character(), c.referenceId, "", ctx.Now()),
+		Description: fmt.Sprintf("Item %d owned %s", c.referenceId, c.Expectation()),
+		ItemId:      c.referenceId,
+	}
+}, CharactersSource, InventorySource)
+
+// itemExpiringWithinEvaluator yields the whole minutes until the earliest expiring copy of the referenced item, held
+// or worn, expires. The condition fails when no unexpired copy has an expiration.
+var itemExpiringWithinEvaluator = NewEvaluator(func(c Condition, ctx ValidationContext) Evaluation {
+	now := ctx.Now()
+	expirations := equippedExpirations(ctx.Character(), c.referenceId, "")
+	if it, ok := inventory2.TypeFromItemId(item.Id(c.referenceId)); ok {
+		for _, a := range ctx.Character().Inventory().CompartmentByType(it).Assets() {
+			if a.TemplateId() == c.referenceId {
+				expirations = append(expirations, a.Expiration())
+			}
+		}
+	}
+
+	var earliest time.Time
+	for _, expiration := range expirations {
+		if expiration.IsZero() || expired(expiration, now) {
+			continue
+		}
+		if earliest.IsZero() || expiration.Before(earliest) {
+			earliest = expiration
+		}
+	}
+	if earliest.IsZero() {
+		return Evaluation{Description: fmt.Sprintf("Item %d minutes until expiration %s (no expiring item)", c.referenceId, c.Expectation()), ItemId: c.referenceId, Unavailable: true}
+	}
+	return Evaluation{
+		ActualValue: int(earliest.Sub(now) / time.Minute),
+		Description: fmt.Sprintf("Item %d minutes until expiration %s", c.referenceId, c.Expectation()),
+		ItemId:      c.referenceId,
+	}
+}, CharactersSource, InventorySource)
+
+// freeSlotsEvaluator yields the number of unoccupied slots in the compartment of the referenced inventory type
+var freeSlotsEvaluator = NewEvaluator(func(c Condition, ctx ValidationContext) Evaluation {
+	it := inventory2.Type(c.referenceId)
+	return Evaluation{
+		ActualValue: ctx.Character().Inventory().CompartmentByType(it).FreeSlots(),
+		Description: fmt.Sprintf("Free %s slots %s", inventoryTypeNames[it], c.Expectation()),
+	}
+}, CharactersSource, InventorySource)
+
+// questRecord returns the referenced quest from a context supplying quests. The quest service only lists quests the
+// character has started, so a quest without a record is not started, has no progress and was never forfeited.
+func questRecord(ctx ValidationContext, questId uint32) quest.Model {
+	if questModel, exists := ctx.Quest(questId); exists {
+		return questModel
+	}
+	return quest.NewModelBuilder().SetId(questId).SetStatus(quest.NOT_STARTED).Build()
+}
+
+// questStatusEvaluator yields the status of the referenced quest
+var questStatusEvaluator = NewEvaluator(func(c Condition, ctx ValidationContext) Evaluation {
+	if !ctx.Supplies(QuestsSource) {
+		return Evaluation{ActualValue: int(quest.UNDEFINED), Description: fmt.Sprintf("Quest %d Status validation requires ValidationContext", c.referenceId), Unavailable: true}
+	}
+	return Evaluation{
+		ActualValue: int(questRecord(ctx, c.referenceId).Status()),
+		Description: fmt.Sprintf("Quest %d Status %s", c.referenceId, c.Expectation()),
+	}
+}, QuestsSource)
+
+// questProgressEvaluator yields the progress of the referenced quest for the condition step
+var questProgressEvaluator = NewEvaluator(func(c Condition, ctx ValidationContext) Evaluation {
+	if !ctx.Supplies(QuestsSource) {
+		return Evaluation{Description: fmt.Sprintf("Quest %d Progress validation (step: %s) requires ValidationContext", c.referenceId, c.step), Unavailable: true}
+	}
+	return Evaluation{
+		ActualValue: questRecord(ctx, c.referenceId).Progress(c.step),
+		Description: fmt.Sprintf("Quest %d Progress (step: %s) %s", c.referenceId, c.step, c.Expectation()),
+	}
+}, QuestsSource)
+
+// questCompletedCountEvaluator yields the number of distinct quests whose status is COMPLETED. The quests service keeps
+// one record per quest, so a repeatable quest counts once however often it was completed, and not at all while it is
+// started again.
+var questCompletedCountEvaluator = NewEvaluator(func(c Condition, ctx ValidationContext) Evaluation {
+	if !ctx.Supplies(QuestsSource) {
+		return Evaluation{Description: "Completed Quests validation requires ValidationContext", Unavailable: true}
+	}
+	completed := 0
+	for _, q := range ctx.Quests() {
+		if q.Status() == quest.COMPLETED {
+			completed++
+		}
+	}
+	return Evaluation{ActualValue: completed, Description: fmt.Sprintf("Completed Quests %s", c.Expectation())}
+}, QuestsSource)
+
+// questElapsed creates an evaluator yielding the whole seconds between a time recorded on the referenced quest and the
+// server time. The condition fails when the quest has no such time, e.g. was never completed.
+func questElapsed(event string, at func(quest.Model) time.Time) Evaluator {
+	return NewEvaluator(func(c Condition, ctx ValidationContext) Evaluation {
+		description := fmt.Sprintf("Quest %d seconds since %s %s", c.referenceId, event, c.Expectation())
+		if !ctx.Supplies(QuestsSource) {
+			return Evaluation{Description: fmt.Sprintf("%s validation requires ValidationContext", description), Unavailable: true}
+		}
+		questModel := questRecord(ctx, c.referenceId)
+		if at(questModel).IsZero() {
+			return Evaluation{Description: fmt.Sprintf("%s (no %s)", description, event), Unavailable: true}
+		}
+		return Evaluation{ActualValue: int(ctx.Now().Sub(at(questModel)) / time.Second), Description: description}
+	}, QuestsSource)
+}
+
+// questCompletedBeforeEvaluator yields the date, as a YYYYMMDD number in the tenant timezone, the referenced quest was
+// last completed on. The condition fails when the quest was never completed.
+var questCompletedBeforeEvaluator = NewEvaluator(func(c Condition, ctx ValidationContext) Evaluation {
+	description := fmt.Sprintf("Quest %d completion date %s", c.referenceId, c.Expectation())
+	if !ctx.Supplies(QuestsSource) {
+		return Evaluation{Description: fmt.Sprintf("%s validation requires ValidationContext", description), Unavailable: true}
+	}
+	questModel := questRecord(ctx, c.referenceId)
+	if questModel.CompletedAt().IsZero() {
+		return Evaluation{Description: fmt.Sprintf("%s (no completion)", description), Unavailable: true}
+	}
+	return Evaluation{ActualValue: dateOf(questModel.CompletedAt().In(ctx.Configuration().Location())), Description: description}
+}, QuestsSource, ConfigurationSource)
+
+// questForfeitCountEvaluator yields the number of times the referenced quest was forfeited
+var questForfeitCountEvaluator = NewEvaluator(func(c Condition, ctx ValidationContext) Evaluation {
+	if !ctx.Supplies(QuestsSource) {
+		return Evaluation{Description: fmt.Sprintf("Quest %d Forfeits validation requires ValidationContext", c.referenceId), Unavailable: true}
+	}
+	return Evaluation{
+		ActualValue: int(questRecord(ctx, c.referenceId).ForfeitCount()),
+		Description: fmt.Sprintf("Quest %d Forfeits %s", c.referenceId, c.Expectation()),
+	}
+}, QuestsSource)
+
+// unclaimedMarriageGiftsEvaluator yields 1 when the character has unclaimed marriage gifts and 0 otherwise
+var unclaimedMarriageGiftsEvaluator = NewEvaluator(func(c Condition, ctx ValidationContext) Evaluation {
+	if !ctx.Supplies(MarriageSource) {
+		return Evaluation{Description: "Unclaimed Marriage Gifts validation requires ValidationContext", Unavailable: true}
+	}
+	actualValue := 0
+	if ctx.Marriage().HasUnclaimedGifts() {
+		actualValue = 1
+	}
+	return Evaluation{ActualValue: actualValue, Description: fmt.Sprintf("Unclaimed Marriage Gifts %s", c.Expectation())}
+}, MarriageSource)
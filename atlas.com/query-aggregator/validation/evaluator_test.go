@@ -0,0 +1,965 @@
+package validation
+
+import (
+	"atlas-query-aggregator/asset"
+	"atlas-query-aggregator/character"
+	"atlas-query-aggregator/character/mock"
+	"atlas-query-aggregator/compartment"
+	"atlas-query-aggregator/guild"
+	"atlas-query-aggregator/guild/member"
+	"atlas-query-aggregator/inventory"
+	"atlas-query-aggregator/quest"
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	inventory_type "github.com/Chronicle20/atlas-constants/inventory"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// TestRegistry_Evaluators tests that every registered condition type has an evaluator reading its explained source
+func TestRegistry_Evaluators(t *testing.T) {
+	for _, d := range ConditionTypes() {
+		t.Run(string(d.Type()), func(t *testing.T) {
+			if d.Evaluator() == nil {
+				t.Fatalf("No evaluator registered")
+			}
+			if !slices.Contains(d.Evaluator().Needs(), d.Source()) {
+				t.Errorf("Needs() = %v, want to contain %s", d.Evaluator().Needs(), d.Source())
+			}
+		})
+	}
+}
+
+// TestFetchPlan tests that the fetch plan is the union of the data needed by every condition in the tree
+func TestFetchPlan(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions []ConditionInput
+		want       []DataSource
+	}{
+		{
+			name:       "Character only",
+			conditions: []ConditionInput{{Type: "level", Operator: ">=", Value: 10}},
+			want:       []DataSource{CharactersSource},
+		},
+		{
+			name:       "Quests alone still include the character",
+			conditions: []ConditionInput{{Type: "questStatus", Operator: "=", Value: 2, ReferenceId: 1001}},
+			want:       []DataSource{CharactersSource, QuestsSource},
+		},
+		{
+			name: "Nested conditions",
+			conditions: []ConditionInput{
+				{Type: "hasUnclaimedMarriageGifts", Operator: "=", Value: 0},
+				{Any: []ConditionInput{
+					{Type: "guildLeader", Operator: "=", Value: 1},
+					{Not: &ConditionInput{Type: "item", Operator: ">=", Value: 1, ReferenceId: 2000001}},
+				}},
+			},
+			want: []DataSource{CharactersSource, InventorySource, GuildsSource, MarriageSource},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conditions := make([]Condition, 0, len(tt.conditions))
+			for _, input := range tt.conditions {
+				condition, err := NewConditionBuilder().FromInput(input).Build()
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				conditions = append(conditions, condition)
+			}
+			if got := fetchPlan(conditions...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fetchPlan() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestProcessorValidateStructured_FetchPlan tests that only the data needed by the conditions is fetched
+func TestProcessorValidateStructured_FetchPlan(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition ConditionInput
+		want      []DataSource
+	}{
+		{name: "Character field", condition: ConditionInput{Type: "fame", Operator: ">=", Value: 0}},
+		{name: "Item", condition: ConditionInput{Type: "item", Operator: ">=", Value: 0, ReferenceId: 2000001}, want: []DataSource{InventorySource}},
+		{name: "Guild rank", condition: ConditionInput{Type: "guildRank", Operator: ">=", Value: 0}, want: []DataSource{GuildsSource}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fetched []DataSource
+			mockCharProcessor := &mock.ProcessorImpl{
+				GetByIdFunc: func(decorators ...model.Decorator[character.Model]) func(characterId uint32) (character.Model, error) {
+					return func(characterId uint32) (character.Model, error) {
+						char := character.NewModelBuilder().SetId(characterId).Build()
+						for _, decorator := range decorators {
+							char = decorator(char)
+						}
+						return char, nil
+					}
+				},
+				WithInventoryFunc: func(m character.Model) (character.Model, error) {
+					fetched = append(fetched, InventorySource)
+					return m, nil
+				},
+				WithGuildFunc: func(m character.Model) (character.Model, error) {
+					fetched = append(fetched, GuildsSource)
+					return m, nil
+				},
+			}
+
+			processor := &ProcessorImpl{
+				l:                  logrus.New(),
+				ctx:                context.Background(),
+				characterProcessor: mockCharProcessor,
+			}
+
+			if _, err := processor.ValidateStructured()(123, []ConditionInput{tt.condition}); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(fetched, tt.want) {
+				t.Errorf("Fetched %v, want %v", fetched, tt.want)
+			}
+		})
+	}
+}
+
+// TestCondition_EvaluateWithContext_Evaluator tests that results are built from the evaluation of the registered evaluator
+func TestCondition_EvaluateWithContext_Evaluator(t *testing.T) {
+	char := character.NewModelBuilder().SetId(1).SetLevel(40).Build()
+
+	tests := []struct {
+		name            string
+		condition       Condition
+		ctx             ValidationContext
+		wantPassed      bool
+		wantActual      int
+		wantDescription string
+	}{
+		{
+			name:            "Compared value",
+			condition:       Condition{conditionType: LevelCondition, operator: Between, min: 30, max: 50},
+			ctx:             NewValidationContext(char),
+			wantPassed:      true,
+			wantActual:      40,
+			wantDescription: "Level between 30 and 50",
+		},
+		{
+			name:            "Unavailable value fails without comparison",
+			condition:       Condition{conditionType: QuestCompletedWithinCondition, operator: GreaterEqual, value: 0, referenceId: 1001},
+			ctx:             NewValidationContext(char),
+			wantPassed:      false,
+			wantActual:      0,
+			wantDescription: "Quest 1001 seconds since completion >= 0 (no completion)",
+		},
+		{
+			name:            "Marriage data supplied by a context",
+			condition:       Condition{conditionType: UnclaimedMarriageGiftsCondition, operator: Equals, value: 0},
+			ctx:             NewValidationContext(char),
+			wantPassed:      true,
+			wantActual:      0,
+			wantDescription: "Unclaimed Marriage Gifts = 0",
+		},
+		{
+			name:            "Unsupported type",
+			condition:       Condition{conditionType: "unknown", operator: Equals, value: 0},
+			ctx:             NewValidationContext(char),
+			wantPassed:      false,
+			wantDescription: "Unsupported condition type: unknown",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.condition.EvaluateWithContext(tt.ctx)
+			if result.Passed != tt.wantPassed || result.ActualValue != tt.wantActual || result.Description != tt.wantDescription {
+				t.Errorf("EvaluateWithContext() = %+v, want passed %v, actual %d, description %q", result, tt.wantPassed, tt.wantActual, tt.wantDescription)
+			}
+		})
+	}
+}
+
+// createTestEquipment creates a character wearing a hat and two copies of a ring, with a cash hat in the cash slot
+// and a spare hat in the bag
+func createTestEquipment() character.Model {
+	compartmentId := uuid.New()
+	equipable := func(id uint32, templateId uint32, slot int16) asset.Model[any] {
+		return asset.NewBuilder[any](id, compartmentId, templateId, id, asset.ReferenceTypeEquipable).
+			SetSlot(slot).
+			SetReferenceData(asset.NewEquipableReferenceDataBuilder().Build()).
+			Build()
+	}
+	cashEquipable := asset.NewBuilder[any](5, compartmentId, 1002186, 5, asset.ReferenceTypeCashEquipable).
+		SetSlot(-101).
+		SetReferenceData(asset.NewCashEquipableReferenceDataBuilder().Build()).
+		Build()
+
+	equip := compartment.NewBuilder(compartmentId, 1, inventory_type.TypeValueEquip, 24).
+		AddAsset(equipable(1, 1002140, -1)).
+		AddAsset(equipable(2, 1112000, -12)).
+		AddAsset(equipable(3, 1112000, -13)).
+		AddAsset(equipable(4, 1002140, 1)).
+		AddAsset(cashEquipable).
+		Build()
+
+	return character.NewModelBuilder().SetId(1).Build().SetInventory(inventory.NewBuilder(1).SetEquipable(equip).Build())
+}
+
+// TestCondition_Evaluate_Equipment tests the equipped and itemOwned conditions against worn and held equipment
+func TestCondition_Evaluate_Equipment(t *testing.T) {
+	char := createTestEquipment()
+
+	tests := []struct {
+		name            string
+		input           ConditionInput
+		wantPassed      bool
+		wantActual      int
+		wantDescription string
+	}{
+		{
+			name:            "Worn item",
+			input:           ConditionInput{Expression: "equipped[1002140]>=1"},
+			wantPassed:      true,
+			wantActual:      1,
+			wantDescription: "Item 1002140 equipped >= 1",
+		},
+		{
+			name:            "Worn in every matching slot",
+			input:           ConditionInput{Expression: "equipped[1112000]=2"},
+			wantPassed:      true,
+			wantActual:      2,
+			wantDescription: "Item 1112000 equipped = 2",
+		},
+		{
+			name:            "Narrowed to a slot type",
+			input:           ConditionInput{Expression: "equipped[1112000:ring1]=1"},
+			wantPassed:      true,
+			wantActual:      1,
+			wantDescription: "Item 1112000 equipped (slot: ring1) = 1",
+		},
+		{
+			name:            "Worn in another slot type",
+			input:           ConditionInput{Type: "equipped", Operator: ">=", Value: 1, ReferenceId: 1002140, Step: "weapon"},
+			wantPassed:      false,
+			wantActual:      0,
+			wantDescription: "Item 1002140 equipped (slot: weapon) >= 1",
+		},
+		{
+			name:            "Cash item worn over its slot",
+			input:           ConditionInput{Expression: "equipped[1002186:hat]>=1"},
+			wantPassed:      true,
+			wantActual:      1,
+			wantDescription: "Item 1002186 equipped (slot: hat) >= 1",
+		},
+		{
+			name:            "Held items are not counted as worn by item",
+			input:           ConditionInput{Expression: "item[1002140]>=2"},
+			wantPassed:      false,
+			wantActual:      1,
+			wantDescription: "Item 1002140 quantity >= 2",
+		},
+		{
+			name:            "Owned counts held and worn items",
+			input:           ConditionInput{Expression: "itemOwned[1002140]>=2"},
+			wantPassed:      true,
+			wantActual:      2,
+			wantDescription: "Item 1002140 owned >= 2",
+		},
+		{
+			name:            "Owned item not held",
+			input:           ConditionInput{Expression: "itemOwned[1302000]>=1"},
+			wantPassed:      false,
+			wantActual:      0,
+			wantDescription: "Item 1302000 owned >= 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, err := NewConditionBuilder().FromInput(tt.input).Build()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			result := condition.Evaluate(char)
+			if result.Passed != tt.wantPassed || result.ActualValue != tt.wantActual || result.Description != tt.wantDescription {
+				t.Errorf("Evaluate() = %+v, want passed %v, actual %d, description %q", result, tt.wantPassed, tt.wantActual, tt.wantDescription)
+			}
+		})
+	}
+}
+
+// TestCondition_Evaluate_FreeSlots tests counting unoccupied compartment slots
+func TestCondition_Evaluate_FreeSlots(t *testing.T) {
+	useCompartment := createTestCompartment(uuid.New(), 1, inventory_type.TypeValueUse, 100)
+
+	// Equipped items remain in the raw equip compartment until the inventory is set on the character
+	equipCompartmentId := uuid.New()
+	equip := compartment.NewBuilder(equipCompartmentId, 1, inventory_type.TypeValueEquip, 24)
+	for i, s := range []int16{-1, -11, -101, 1, 2} {
+		equip.AddAsset(asset.NewBuilder[any](uint32(i+1), equipCompartmentId, 1302000, uint32(i+1), asset.ReferenceTypeEquipable).
+			SetSlot(s).
+			SetReferenceData(asset.NewEquipableReferenceDataBuilder().Build()).
+			Build())
+	}
+	inv := inventory.NewBuilder(1).SetEquipable(equip.Build()).SetConsumable(useCompartment).Build()
+
+	characters := map[string]character.Model{
+		"raw inventory": character.NewModelBuilder().SetId(1).SetInventory(inv).Build(),
+		"set inventory": character.NewModelBuilder().SetId(1).Build().SetInventory(inv),
+	}
+
+	tests := []struct {
+		name            string
+		expression      string
+		wantActual      int
+		wantDescription string
+	}{
+		{name: "Use compartment", expression: "freeSlots[2]>=1", wantActual: 97, wantDescription: "Free use slots >= 1"},
+		{name: "Equipped items ignored", expression: "freeSlots[1]>=1", wantActual: 22, wantDescription: "Free equip slots >= 1"},
+		{name: "Empty compartment", expression: "freeSlots[4]>=1", wantActual: 0, wantDescription: "Free etc slots >= 1"},
+	}
+
+	for _, tt := range tests {
+		for variant, char := range characters {
+			t.Run(tt.name+" with "+variant, func(t *testing.T) {
+				condition, err := NewConditionBuilder().FromExpression(tt.expression).Build()
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				result := condition.Evaluate(char)
+				if result.ActualValue != tt.wantActual || result.Description != tt.wantDescription {
+					t.Errorf("Evaluate() = %+v, want actual %d, description %q", result, tt.wantActual, tt.wantDescription)
+				}
+			})
+		}
+	}
+}
+
+// TestCondition_Evaluate_JobLineage tests the job branch, advancement and family conditions
+func TestCondition_Evaluate_JobLineage(t *testing.T) {
+	crusader := character.NewModelBuilder().SetId(1).SetJobId(111).Build()
+	evan := character.NewModelBuilder().SetId(2).SetJobId(2215).Build()
+
+	tests := []struct {
+		name            string
+		char            character.Model
+		expression      string
+		wantPassed      bool
+		wantDescription string
+	}{
+		{name: "Warrior at third job or beyond", char: crusader, expression: "jobBranch[100]=1 && jobAdvancement>=3", wantPassed: true, wantDescription: "All of 2 conditions (2 passed)"},
+		{name: "Not in another branch", char: crusader, expression: "jobBranch[120]=1", wantPassed: false, wantDescription: "Job Branch 120 = 1"},
+		{name: "Explorer family", char: crusader, expression: "jobFamily=0", wantPassed: true, wantDescription: "Job Family = 0"},
+		{name: "Evan stage advancement", char: evan, expression: "jobAdvancement=3", wantPassed: true, wantDescription: "Job Advancement = 3"},
+		{name: "Evan family", char: evan, expression: "jobFamily=3 && jobBranch[2001]=1", wantPassed: true, wantDescription: "All of 2 conditions (2 passed)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, err := NewConditionBuilder().FromExpression(tt.expression).Build()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			result := condition.Evaluate(tt.char)
+			if result.Passed != tt.wantPassed || result.Description != tt.wantDescription {
+				t.Errorf("Evaluate() = %+v, want passed %v, description %q", result, tt.wantPassed, tt.wantDescription)
+			}
+		})
+	}
+}
+
+// TestCondition_Evaluate_Vitals tests the HP, MP, AP, SP and experience conditions
+func TestCondition_Evaluate_Vitals(t *testing.T) {
+	char := character.NewModelBuilder().
+		SetId(1).
+		SetLevel(10).
+		SetExperience(858).
+		SetHp(400).
+		SetMaxHp(1000).
+		SetMp(300).
+		SetMaxMp(300).
+		SetAp(0).
+		SetHpMpUsed(2).
+		SetJobId(2211).
+		SetSp("0,1,5").
+		SetGachaponExperience(120).
+		Build()
+
+	tests := []struct {
+		name            string
+		expression      string
+		wantPassed      bool
+		wantDescription string
+	}{
+		{name: "HP below half", expression: "hpPercent<50", wantPassed: true, wantDescription: "HP % < 50"},
+		{name: "Full MP", expression: "mpPercent=100 && mp=300 && maxMp>=300", wantPassed: true, wantDescription: "All of 3 conditions (3 passed)"},
+		{name: "Absolute HP", expression: "hp>=400 && maxHp=1000", wantPassed: true, wantDescription: "All of 2 conditions (2 passed)"},
+		{name: "AP spent on HP and MP", expression: "ap=0 && hpMpUsed>0", wantPassed: true, wantDescription: "All of 2 conditions (2 passed)"},
+		{name: "Remaining SP of the Evan skill book", expression: "remainingSp=5", wantPassed: true, wantDescription: "Remaining SP = 5"},
+		{name: "Experience", expression: "experience between [800, 900]", wantPassed: true, wantDescription: "Experience between 800 and 900"},
+		{name: "Experience percent", expression: "experiencePercent>50", wantPassed: false, wantDescription: "Experience % > 50"},
+		{name: "Gachapon experience", expression: "gachaponExperience>=100", wantPassed: true, wantDescription: "Gachapon Experience >= 100"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, err := NewConditionBuilder().FromExpression(tt.expression).Build()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			result := condition.Evaluate(char)
+			if result.Passed != tt.wantPassed || result.Description != tt.wantDescription {
+				t.Errorf("Evaluate() = %+v, want passed %v, description %q", result, tt.wantPassed, tt.wantDescription)
+			}
+		})
+	}
+}
+
+// TestCondition_Evaluate_Appearance tests the hair, face and skin conditions and their style and color decomposition
+func TestCondition_Evaluate_Appearance(t *testing.T) {
+	char := character.NewModelBuilder().SetId(1).SetHair(30037).SetFace(20401).SetSkinColor(2).Build()
+
+	tests := []struct {
+		name            string
+		expression      string
+		wantPassed      bool
+		wantDescription string
+	}{
+		{name: "Hair", expression: "hair=30037", wantPassed: true, wantDescription: "Hair = 30037"},
+		{name: "Hair style regardless of color", expression: "hairStyle=30030", wantPassed: true, wantDescription: "Hair Style = 30030"},
+		{name: "Hair color", expression: "hairColor=0", wantPassed: false, wantDescription: "Hair Color = 0"},
+		{name: "Face style regardless of lens", expression: "faceStyle=20001 && face!=20001", wantPassed: true, wantDescription: "All of 2 conditions (2 passed)"},
+		{name: "Face color", expression: "faceColor=4", wantPassed: true, wantDescription: "Face Color = 4"},
+		{name: "Skin color", expression: "skinColor=2", wantPassed: true, wantDescription: "Skin Color = 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, err := NewConditionBuilder().FromExpression(tt.expression).Build()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			result := condition.Evaluate(char)
+			if result.Passed != tt.wantPassed || result.Description != tt.wantDescription {
+				t.Errorf("Evaluate() = %+v, want passed %v, description %q", result, tt.wantPassed, tt.wantDescription)
+			}
+		})
+	}
+
+	if _, err := NewConditionBuilder().FromExpression("hairColor=10").Build(); err == nil {
+		t.Error("Expected an error for a hair color out of range")
+	}
+}
+
+// TestCondition_EvaluateWithContext_Maps tests the map region and continent conditions and membership of tenant configured map groups
+func TestCondition_EvaluateWithContext_Maps(t *testing.T) {
+	char := character.NewModelBuilder().SetId(1).SetMapId(910000002).Build()
+	configuration := NewTenantConfiguration(map[string][]uint32{
+		"freeMarket": {910000001, 910000002, 910000003},
+		"pqLobby":    {103000000, 221024500},
+	}, nil)
+	ctx := NewValidationContext(char).WithConfiguration(configuration)
+
+	tests := []struct {
+		name            string
+		input           ConditionInput
+		wantPassed      bool
+		wantActual      int
+		wantValues      []int
+		wantDescription string
+	}{
+		{name: "Region", input: ConditionInput{Expression: "mapRegion=910"}, wantPassed: true, wantActual: 910, wantDescription: "Map Region = 910"},
+		{name: "Region list", input: ConditionInput{Expression: "mapRegion in [220, 221, 222]"}, wantPassed: false, wantActual: 910, wantValues: []int{220, 221, 222}, wantDescription: "Map Region in [220, 221, 222]"},
+		{name: "Continent", input: ConditionInput{Expression: "mapContinent=9"}, wantPassed: true, wantActual: 9, wantDescription: "Map Continent = 9"},
+		{name: "Continent list", input: ConditionInput{Expression: "mapContinent in [1, 2]"}, wantPassed: false, wantActual: 9, wantValues: []int{1, 2}, wantDescription: "Map Continent in [1, 2]"},
+		{name: "In list", input: ConditionInput{Expression: "mapIn in [100000000, 910000002]"}, wantPassed: true, wantActual: 910000002, wantValues: []int{100000000, 910000002}, wantDescription: "Map in [100000000, 910000002]"},
+		{name: "Not in list", input: ConditionInput{Expression: "mapIn notIn [100000000]"}, wantPassed: true, wantActual: 910000002, wantValues: []int{100000000}, wantDescription: "Map notIn [100000000]"},
+		{name: "In group", input: ConditionInput{Type: "mapIn", Operator: "in", Step: "freeMarket"}, wantPassed: true, wantActual: 910000002, wantValues: []int{910000001, 910000002, 910000003}, wantDescription: "Map in freeMarket"},
+		{name: "Not in group", input: ConditionInput{Type: "mapIn", Operator: "in", Step: "pqLobby"}, wantPassed: false, wantActual: 910000002, wantValues: []int{103000000, 221024500}, wantDescription: "Map in pqLobby"},
+		{name: "Outside group", input: ConditionInput{Type: "mapIn", Operator: "notIn", Step: "pqLobby"}, wantPassed: true, wantActual: 910000002, wantValues: []int{103000000, 221024500}, wantDescription: "Map notIn pqLobby"},
+		{name: "Unknown group", input: ConditionInput{Type: "mapIn", Operator: "notIn", Step: "henesysMarket"}, wantPassed: false, wantActual: 910000002, wantDescription: "Map notIn henesysMarket (no such map group)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, err := NewConditionBuilder().FromInput(tt.input).Build()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			result := condition.EvaluateWithContext(ctx)
+			if result.Passed != tt.wantPassed || result.ActualValue != tt.wantActual || result.Description != tt.wantDescription {
+				t.Errorf("EvaluateWithContext() = %+v, want passed %v, actual %d, description %q", result, tt.wantPassed, tt.wantActual, tt.wantDescription)
+			}
+			if !reflect.DeepEqual(result.Values, tt.wantValues) {
+				t.Errorf("Values = %v, want %v", result.Values, tt.wantValues)
+			}
+		})
+	}
+
+	invalid := []ConditionInput{
+		{Type: "mapIn", Operator: "in"},
+		{Type: "mapIn", Operator: "=", Value: 100000000},
+		{Type: "mapIn", Operator: "in", Values: []int{100000000}, Step: "freeMarket"},
+		{Expression: "mapIn[:freeMarket]=1"},
+	}
+	for _, input := range invalid {
+		if _, err := NewConditionBuilder().FromInput(input).Build(); err == nil {
+			t.Errorf("Expected an error for map condition %+v", input)
+		}
+		if err := validateConditionInput(input); err == nil {
+			t.Errorf("Expected a validation error for map condition %+v", input)
+		}
+	}
+}
+
+// TestProcessorValidateStructured_Configuration tests that the tenant configuration is only read when a condition refers
+// to it, and that a failed read is explained
+func TestProcessorValidateStructured_Configuration(t *testing.T) {
+	tests := []struct {
+		name       string
+		condition  ConditionInput
+		err        error
+		wantReads  int
+		wantPassed bool
+	}{
+		{name: "Not needed", condition: ConditionInput{Type: "mapRegion", Operator: "=", Value: 910}, wantReads: 0, wantPassed: true},
+		{name: "Map list", condition: ConditionInput{Type: "mapIn", Operator: "in", Values: []int{910000001}}, wantReads: 0, wantPassed: true},
+		{name: "Map group", condition: ConditionInput{Type: "mapIn", Operator: "in", Step: "freeMarket"}, wantReads: 1, wantPassed: true},
+		{name: "Failed read", condition: ConditionInput{Type: "mapIn", Operator: "in", Step: "freeMarket"}, err: errors.New("database closed"), wantReads: 1, wantPassed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reads := 0
+			processor := &ProcessorImpl{
+				l:   logrus.New(),
+				ctx: context.Background(),
+				characterProcessor: &mock.ProcessorImpl{
+					GetByIdFunc: func(decorators ...model.Decorator[character.Model]) func(characterId uint32) (character.Model, error) {
+						return func(characterId uint32) (character.Model, error) {
+							return character.NewModelBuilder().SetId(characterId).SetMapId(910000001).Build(), nil
+						}
+					},
+				},
+				configuration: func() (TenantConfiguration, error) {
+					reads++
+					if tt.err != nil {
+						return TenantConfiguration{}, tt.err
+					}
+					return NewTenantConfiguration(map[string][]uint32{"freeMarket": {910000001}}, nil), nil
+				},
+			}
+
+			result, err := processor.ValidateStructured(Explain)(123, []ConditionInput{tt.condition})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if reads != tt.wantReads {
+				t.Errorf("Configuration read %d times, want %d", reads, tt.wantReads)
+			}
+			if result.Results()[0].Passed != tt.wantPassed {
+				t.Errorf("Passed = %v, want %v", result.Results()[0].Passed, tt.wantPassed)
+			}
+			if tt.err != nil {
+				explanation := result.Results()[0].Explanation
+				if explanation == nil || explanation.Source != ConfigurationSource || explanation.Succeeded || explanation.Error != tt.err.Error() {
+					t.Errorf("Expected a failed configuration read to be explained, got %+v", explanation)
+				}
+			}
+		})
+	}
+}
+
+// TestCondition_EvaluateWithContext_ServerTime tests the date, day of week and hour conditions against the server time
+// read in the tenant timezone
+func TestCondition_EvaluateWithContext_ServerTime(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// Saturday 31 October 2026, 23:30 in New York is Sunday 1 November 2026, 03:30 in UTC
+	now := time.Date(2026, time.November, 1, 3, 30, 0, 0, time.UTC)
+	char := character.NewModelBuilder().SetId(1).Build()
+
+	tests := []struct {
+		name            string
+		location        *time.Location
+		expression      string
+		wantPassed      bool
+		wantActual      int
+		wantDescription string
+	}{
+		{name: "Date in range", location: newYork, expression: "dateRange between [20261025, 20261031]", wantPassed: true, wantActual: 20261031, wantDescription: "Date between 20261025 and 20261031"},
+		{name: "Date after range in UTC", expression: "dateRange between [20261025, 20261031]", wantPassed: false, wantActual: 20261101, wantDescription: "Date between 20261025 and 20261031"},
+		{name: "Weekend", location: newYork, expression: "dayOfWeek in [0, 6]", wantPassed: true, wantActual: 6, wantDescription: "Day of Week in [0, 6]"},
+		{name: "Weekday", location: newYork, expression: "dayOfWeek between [1, 5]", wantPassed: false, wantActual: 6, wantDescription: "Day of Week between 1 and 5"},
+		{name: "Evening", location: newYork, expression: "hourRange between [18, 23]", wantPassed: true, wantActual: 23, wantDescription: "Hour between 18 and 23"},
+		{name: "Evening in UTC", expression: "hourRange between [18, 23]", wantPassed: false, wantActual: 3, wantDescription: "Hour between 18 and 23"},
+		{name: "Night before midnight", location: newYork, expression: "hourRange between [22, 2]", wantPassed: true, wantActual: 23, wantDescription: "Hour between 22 and 2"},
+		{name: "Night after midnight", expression: "hourRange between [22, 2]", wantPassed: false, wantActual: 3, wantDescription: "Hour between 22 and 2"},
+		{name: "Long weekend", location: newYork, expression: "dayOfWeek between [5, 1]", wantPassed: true, wantActual: 6, wantDescription: "Day of Week between 5 and 1"},
+		{name: "Month and day", expression: "monthDay between [1025, 1105]", wantPassed: true, wantActual: 1101, wantDescription: "Month and Day between 1025 and 1105"},
+		{name: "Month and day outside season", expression: "monthDay between [1220, 105]", wantPassed: false, wantActual: 1101, wantDescription: "Month and Day between 1220 and 105"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, err := NewConditionBuilder().FromExpression(tt.expression).Build()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			ctx := NewValidationContext(char).WithNow(now).WithConfiguration(NewTenantConfiguration(nil, tt.location))
+			result := condition.EvaluateWithContext(ctx)
+			if result.Passed != tt.wantPassed || result.ActualValue != tt.wantActual || result.Description != tt.wantDescription {
+				t.Errorf("EvaluateWithContext() = %+v, want passed %v, actual %d, description %q", result, tt.wantPassed, tt.wantActual, tt.wantDescription)
+			}
+			if result.ServerTime == nil || !result.ServerTime.Equal(now) || result.ServerTime.Location() != ctx.Configuration().Location() {
+				t.Errorf("ServerTime = %v, want %v in %s", result.ServerTime, now, ctx.Configuration().Location())
+			}
+		})
+	}
+
+	if _, err := NewConditionBuilder().FromExpression("hourRange=24").Build(); err == nil {
+		t.Error("Expected an error for an hour outside the day")
+	}
+	if _, err := NewConditionBuilder().FromExpression("dateRange between [20270105, 20261220]").Build(); err == nil {
+		t.Error("Expected an error for a date range ending before it starts")
+	}
+}
+
+// TestCondition_EvaluateWithContext_RecurringRanges tests that month and day and hour ranges wrap past the year end and
+// midnight
+func TestCondition_EvaluateWithContext_RecurringRanges(t *testing.T) {
+	char := character.NewModelBuilder().SetId(1).Build()
+
+	tests := []struct {
+		name       string
+		now        time.Time
+		expression string
+		wantPassed bool
+		wantActual int
+	}{
+		{name: "Season start", now: time.Date(2026, time.December, 20, 0, 0, 0, 0, time.UTC), expression: "monthDay between [1220, 105]", wantPassed: true, wantActual: 1220},
+		{name: "New Year's Eve", now: time.Date(2026, time.December, 31, 12, 0, 0, 0, time.UTC), expression: "monthDay between [1220, 105]", wantPassed: true, wantActual: 1231},
+		{name: "New Year's Day", now: time.Date(2027, time.January, 1, 12, 0, 0, 0, time.UTC), expression: "monthDay between [1220, 105]", wantPassed: true, wantActual: 101},
+		{name: "Season end", now: time.Date(2027, time.January, 5, 23, 59, 0, 0, time.UTC), expression: "monthDay between [1220, 105]", wantPassed: true, wantActual: 105},
+		{name: "After season", now: time.Date(2027, time.January, 6, 0, 0, 0, 0, time.UTC), expression: "monthDay between [1220, 105]", wantPassed: false, wantActual: 106},
+		{name: "Before season", now: time.Date(2027, time.December, 19, 23, 59, 0, 0, time.UTC), expression: "monthDay between [1220, 105]", wantPassed: false, wantActual: 1219},
+		{name: "Outside season", now: time.Date(2027, time.December, 19, 0, 0, 0, 0, time.UTC), expression: "monthDay notIn [1224, 1225]", wantPassed: true, wantActual: 1219},
+		{name: "Before midnight", now: time.Date(2026, time.October, 17, 22, 0, 0, 0, time.UTC), expression: "hourRange between [22, 2]", wantPassed: true, wantActual: 22},
+		{name: "Midnight", now: time.Date(2026, time.October, 18, 0, 30, 0, 0, time.UTC), expression: "hourRange between [22, 2]", wantPassed: true, wantActual: 0},
+		{name: "After midnight", now: time.Date(2026, time.October, 18, 2, 59, 0, 0, time.UTC), expression: "hourRange between [22, 2]", wantPassed: true, wantActual: 2},
+		{name: "Morning", now: time.Date(2026, time.October, 18, 3, 0, 0, 0, time.UTC), expression: "hourRange between [22, 2]", wantPassed: false, wantActual: 3},
+		{name: "Evening", now: time.Date(2026, time.October, 18, 21, 59, 0, 0, time.UTC), expression: "hourRange between [22, 2]", wantPassed: false, wantActual: 21},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, err := NewConditionBuilder().FromExpression(tt.expression).Build()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			result := condition.EvaluateWithContext(NewValidationContext(char).WithNow(tt.now).WithConfiguration(NewTenantConfiguration(nil, nil)))
+			if result.Passed != tt.wantPassed || result.ActualValue != tt.wantActual {
+				t.Errorf("EvaluateWithContext() = %+v, want passed %v, actual %d", result, tt.wantPassed, tt.wantActual)
+			}
+		})
+	}
+
+	if _, err := NewConditionBuilder().FromExpression("monthDay=1300").Build(); err == nil {
+		t.Error("Expected an error for a month and day outside the year")
+	}
+}
+
+// TestCondition_EvaluateWithContext_QuestHistory tests the quest completion count, completion and start time, and forfeit
+// conditions
+func TestCondition_EvaluateWithContext_QuestHistory(t *testing.T) {
+	now := time.Date(2026, time.October, 17, 12, 0, 0, 0, time.UTC)
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	char := character.NewModelBuilder().SetId(1).Build()
+	ctx := NewValidationContextBuilder(char).
+		AddQuest(quest.NewModelBuilder().SetId(1001).SetStatus(quest.COMPLETED).SetStartedAt(now.Add(-2 * time.Hour)).SetCompletedAt(now.Add(-90 * time.Minute)).Build()).
+		AddQuest(quest.NewModelBuilder().SetId(1002).SetStatus(quest.STARTED).SetStartedAt(now.Add(-30 * time.Second)).SetCompletedAt(now.Add(-48 * time.Hour)).SetForfeitCount(2).Build()).
+		AddQuest(quest.NewModelBuilder().SetId(1003).SetStatus(quest.COMPLETED).SetCompletedAt(time.Date(2026, time.October, 1, 2, 0, 0, 0, time.UTC)).Build()).
+		AddQuest(quest.NewModelBuilder().SetId(1004).SetStatus(quest.NOT_STARTED).Build()).
+		SetNow(now).
+		SetConfiguration(NewTenantConfiguration(nil, newYork)).
+		Build()
+
+	tests := []struct {
+		name            string
+		expression      string
+		wantPassed      bool
+		wantActual      int
+		wantDescription string
+	}{
+		{name: "Completed count", expression: "questCompletedCount>=2", wantPassed: true, wantActual: 2, wantDescription: "Completed Quests >= 2"},
+		{name: "Completed within", expression: "questCompletedWithin[1001]<=7200", wantPassed: true, wantActual: 5400, wantDescription: "Quest 1001 seconds since completion <= 7200"},
+		{name: "Daily cooldown elapsed", expression: "questCompletedWithin[1002]>=86400", wantPassed: true, wantActual: 172800, wantDescription: "Quest 1002 seconds since completion >= 86400"},
+		{name: "Never completed", expression: "questCompletedWithin[1004]>=0", wantPassed: false, wantActual: 0, wantDescription: "Quest 1004 seconds since completion >= 0 (no completion)"},
+		{name: "Unknown quest", expression: "questCompletedWithin[1005]>=0", wantPassed: false, wantActual: 0, wantDescription: "Quest 1005 seconds since completion >= 0 (no completion)"},
+		{name: "Unknown quest not started", expression: "questStatus[1005]=1", wantPassed: true, wantActual: 1, wantDescription: "Quest 1005 Status = 1"},
+		{name: "Unknown quest without progress", expression: "questProgress[1005:mobsKilled]=0", wantPassed: true, wantActual: 0, wantDescription: "Quest 1005 Progress (step: mobsKilled) = 0"},
+		{name: "Unknown quest never started", expression: "questStartedWithin[1005]>=0", wantPassed: false, wantActual: 0, wantDescription: "Quest 1005 seconds since start >= 0 (no start)"},
+		{name: "Unknown quest never completed", expression: "questCompletedBefore[1005]<20261001", wantPassed: false, wantActual: 0, wantDescription: "Quest 1005 completion date < 20261001 (no completion)"},
+		{name: "Started within", expression: "questStartedWithin[1002]<60", wantPassed: true, wantActual: 30, wantDescription: "Quest 1002 seconds since start < 60"},
+		{name: "Completed before in tenant timezone", expression: "questCompletedBefore[1003]<20261001", wantPassed: true, wantActual: 20260930, wantDescription: "Quest 1003 completion date < 20261001"},
+		{name: "Completed today", expression: "questCompletedBefore[1001]<20261017", wantPassed: false, wantActual: 20261017, wantDescription: "Quest 1001 completion date < 20261017"},
+		{name: "Forfeits", expression: "questForfeitCount[1002]>=2", wantPassed: true, wantActual: 2, wantDescription: "Quest 1002 Forfeits >= 2"},
+		{name: "Forfeits of unknown quest", expression: "questForfeitCount[1005]=0", wantPassed: true, wantActual: 0, wantDescription: "Quest 1005 Forfeits = 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, err := NewConditionBuilder().FromExpression(tt.expression).Build()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			result := condition.EvaluateWithContext(ctx)
+			if result.Passed != tt.wantPassed || result.ActualValue != tt.wantActual || result.Description != tt.wantDescription {
+				t.Errorf("EvaluateWithContext() = %+v, want passed %v, actual %d, description %q", result, tt.wantPassed, tt.wantActual, tt.wantDescription)
+			}
+		})
+	}
+
+	if _, err := NewConditionBuilder().FromExpression("questCompletedBefore[1001]>20261001").Build(); err == nil {
+		t.Error("Expected an error for a completion date compared with >")
+	}
+	condition, err := NewConditionBuilder().FromExpression("questCompletedCount>=0").Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result := condition.Evaluate(char); result.Passed {
+		t.Errorf("Expected the completed quest count to require a ValidationContext, got %+v", result)
+	}
+}
+
+// TestCondition_EvaluateWithContext_QuestCompletedCount tests that the completed quest count counts distinct quests in
+// the COMPLETED status rather than completions of repeatable quests
+func TestCondition_EvaluateWithContext_QuestCompletedCount(t *testing.T) {
+	now := time.Date(2026, time.October, 17, 12, 0, 0, 0, time.UTC)
+	char := character.NewModelBuilder().SetId(1).Build()
+
+	tests := []struct {
+		name       string
+		quests     []quest.Model
+		wantActual int
+	}{
+		{
+			name:       "Repeatable quest completed again",
+			quests:     []quest.Model{quest.NewModelBuilder().SetId(2001).SetStatus(quest.COMPLETED).SetCompletedAt(now.Add(-time.Hour)).Build()},
+			wantActual: 1,
+		},
+		{
+			name:       "Repeatable quest started again",
+			quests:     []quest.Model{quest.NewModelBuilder().SetId(2001).SetStatus(quest.STARTED).SetStartedAt(now.Add(-time.Minute)).SetCompletedAt(now.Add(-time.Hour)).Build()},
+			wantActual: 0,
+		},
+		{
+			name: "Distinct quests",
+			quests: []quest.Model{
+				quest.NewModelBuilder().SetId(2001).SetStatus(quest.COMPLETED).SetCompletedAt(now.Add(-time.Hour)).Build(),
+				quest.NewModelBuilder().SetId(2002).SetStatus(quest.COMPLETED).SetCompletedAt(now.Add(-2 * time.Hour)).Build(),
+			},
+			wantActual: 2,
+		},
+	}
+
+	condition, err := NewConditionBuilder().FromExpression("questCompletedCount>=2").Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewValidationContextBuilder(char).SetNow(now)
+			for _, q := range tt.quests {
+				builder.AddQuest(q)
+			}
+			result := condition.EvaluateWithContext(builder.Build())
+			if result.ActualValue != tt.wantActual || result.Passed != (tt.wantActual >= 2) {
+				t.Errorf("EvaluateWithContext() = %+v, want actual %d", result, tt.wantActual)
+			}
+		})
+	}
+}
+
+// TestProcessorValidateStructured_Clock tests that every condition of a validation is evaluated at the time read from
+// the processor clock, and that the clock can be given to NewProcessor
+func TestProcessorValidateStructured_Clock(t *testing.T) {
+	now := time.Date(2026, time.October, 17, 20, 0, 0, 0, time.UTC)
+	reads := 0
+	processor := &ProcessorImpl{
+		l:   logrus.New(),
+		ctx: context.Background(),
+		characterProcessor: &mock.ProcessorImpl{
+			GetByIdFunc: func(decorators ...model.Decorator[character.Model]) func(characterId uint32) (character.Model, error) {
+				return func(characterId uint32) (character.Model, error) {
+					return character.NewModelBuilder().SetId(characterId).Build(), nil
+				}
+			},
+		},
+		configuration: func() (TenantConfiguration, error) {
+			return NewTenantConfiguration(nil, nil), nil
+		},
+		clock: func() time.Time {
+			reads++
+			return now
+		},
+	}
+
+	result, err := processor.ValidateStructured()(123, []ConditionInput{
+		{Type: "dayOfWeek", Operator: "=", Value: 6},
+		{Type: "hourRange", Operator: ">=", Value: 18},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if reads != 1 {
+		t.Errorf("Clock read %d times, want 1", reads)
+	}
+	for _, r := range result.Results() {
+		if !r.Passed || r.ServerTime == nil || !r.ServerTime.Equal(now) {
+			t.Errorf("Result = %+v, want passed at %v", r, now)
+		}
+	}
+	configured := NewProcessor(logrus.New(), context.Background(), nil, WithClock(func() time.Time { return now })).(*ProcessorImpl)
+	if !configured.now().Equal(now) {
+		t.Errorf("NewProcessor() with WithClock reads %v, want %v", configured.now(), now)
+	}
+}
+
+// TestCondition_Evaluate_GuildAggregates tests the guild points, capacity and member count conditions
+func TestCondition_Evaluate_GuildAggregates(t *testing.T) {
+	g, err := guild.Extract(guild.RestModel{
+		Id:       1001,
+		Points:   5000,
+		Capacity: 30,
+		LeaderId: 1,
+		Members: []member.RestModel{
+			{CharacterId: 1, Level: 120, Rank: 1, Online: true},
+			{CharacterId: 2, Level: 70, Rank: 2, Online: false},
+			{CharacterId: 3, Level: 50, Rank: 3, Online: true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	leader := character.NewModelBuilder().SetId(1).SetGuild(g).Build()
+	guildless := character.NewModelBuilder().SetId(4).Build()
+
+	tests := []struct {
+		name            string
+		char            character.Model
+		expression      string
+		wantPassed      bool
+		wantActual      int
+		wantDescription string
+	}{
+		{name: "Guild points", char: leader, expression: "guildPoints>=5000", wantPassed: true, wantActual: 5000, wantDescription: "Guild Points >= 5000"},
+		{name: "Guild capacity", char: leader, expression: "guildCapacity<100", wantPassed: true, wantActual: 30, wantDescription: "Guild Capacity < 100"},
+		{name: "Guild member count", char: leader, expression: "guildMemberCount>=6", wantPassed: false, wantActual: 3, wantDescription: "Guild Members >= 6"},
+		{name: "Online members", char: leader, expression: "guildOnlineMemberCount=2", wantPassed: true, wantActual: 2, wantDescription: "Guild Online Members = 2"},
+		{name: "Members at level", char: leader, expression: "guildMembersAtLevel[70]>=2", wantPassed: true, wantActual: 2, wantDescription: "Guild Members at or above Level 70 >= 2"},
+		{name: "Members at rank", char: leader, expression: "guildMembersAtRank[2]=2", wantPassed: true, wantActual: 2, wantDescription: "Guild Members at or above Rank 2 = 2"},
+		{name: "Not in guild", char: guildless, expression: "guildMemberCount>=1", wantPassed: false, wantActual: 0, wantDescription: "Guild Members >= 1 (character not in guild)"},
+		{name: "Members at level not in guild", char: guildless, expression: "guildMembersAtLevel[1]>=1", wantPassed: false, wantActual: 0, wantDescription: "Guild Members at or above Level 1 >= 1 (character not in guild)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, err := NewConditionBuilder().FromExpression(tt.expression).Build()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			result := condition.Evaluate(tt.char)
+			if result.Passed != tt.wantPassed || result.ActualValue != tt.wantActual || result.Description != tt.wantDescription {
+				t.Errorf("Evaluate() = %+v, want passed %v, actual %d, description %q", result, tt.wantPassed, tt.wantActual, tt.wantDescription)
+			}
+		})
+	}
+
+	if _, err := NewConditionBuilder().FromExpression("guildMembersAtRank[6]>=1").Build(); err == nil {
+		t.Error("Expected an error for an unknown guild rank")
+	}
+}
+
+// TestCondition_EvaluateWithContext_Expiration tests that expired items are not counted and expiring items are found
+func TestCondition_EvaluateWithContext_Expiration(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	useId := uuid.New()
+	equipId := uuid.New()
+
+	held := func(id uint32, templateId uint32, quantity uint32, expiration time.Time) asset.Model[any] {
+		return asset.NewBuilder[any](id, useId, templateId, id, asset.ReferenceTypeConsumable).
+			SetSlot(int16(id)).
+			SetExpiration(expiration).
+			SetReferenceData(asset.NewConsumableReferenceDataBuilder().SetQuantity(quantity).Build()).
+			Build()
+	}
+	worn := func(id uint32, templateId uint32, slot int16, expiration time.Time) asset.Model[any] {
+		return asset.NewBuilder[any](id, equipId, templateId, id, asset.ReferenceTypeEquipable).
+			SetSlot(slot).
+			SetExpiration(expiration).
+			SetReferenceData(asset.NewEquipableReferenceDataBuilder().Build()).
+			Build()
+	}
+
+	use := compartment.NewBuilder(useId, 1, inventory_type.TypeValueUse, 24).
+		AddAsset(held(1, 2000001, 10, time.Time{})).
+		AddAsset(held(2, 2000001, 5, now.Add(-time.Hour))).
+		AddAsset(held(3, 2000001, 3, now.Add(30*time.Minute))).
+		AddAsset(held(4, 2000002, 1, now.Add(-time.Minute))).
+		Build()
+	equip := compartment.NewBuilder(equipId, 1, inventory_type.TypeValueEquip, 24).
+		AddAsset(worn(5, 1002140, -1, now.Add(2*time.Hour))).
+		AddAsset(worn(6, 1002140, 1, now.Add(-time.Hour))).
+		AddAsset(worn(7, 1302000, -11, now.Add(-time.Hour))).
+		Build()
+
+	char := character.NewModelBuilder().SetId(1).Build().
+		SetInventory(inventory.NewBuilder(1).SetConsumable(use).SetEquipable(equip).Build())
+	ctx := NewValidationContext(char).WithNow(now)
+
+	tests := []struct {
+		name            string
+		expression      string
+		wantPassed      bool
+		wantActual      int
+		wantDescription string
+	}{
+		{name: "Expired quantity not counted", expression: "item[2000001]=13", wantPassed: true, wantActual: 13, wantDescription: "Item 2000001 quantity = 13"},
+		{name: "Only expired copies", expression: "item[2000002]>=1", wantPassed: false, wantActual: 0, wantDescription: "Item 2000002 quantity >= 1"},
+		{name: "Expired worn item", expression: "equipped[1302000]>=1", wantPassed: false, wantActual: 0, wantDescription: "Item 1302000 equipped >= 1"},
+		{name: "Owned excludes expired held copy", expression: "itemOwned[1002140]=1", wantPassed: true, wantActual: 1, wantDescription: "Item 1002140 owned = 1"},
+		{name: "Held item expiring soon", expression: "itemExpiringWithin[2000001]<=30", wantPassed: true, wantActual: 30, wantDescription: "Item 2000001 minutes until expiration <= 30"},
+		{name: "Worn item expiring later", expression: "itemExpiringWithin[1002140]<=60", wantPassed: false, wantActual: 120, wantDescription: "Item 1002140 minutes until expiration <= 60"},
+		{name: "No expiring copy", expression: "itemExpiringWithin[2000002]<=60", wantPassed: false, wantActual: 0, wantDescription: "Item 2000002 minutes until expiration <= 60 (no expiring item)"},
+		{name: "Refuse exchange unless no copy expires soon", expression: "!itemExpiringWithin[2000002]<=60", wantPassed: true, wantActual: 0, wantDescription: "Not (Item 2000002 minutes until expiration <= 60 (no expiring item))"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, err := NewConditionBuilder().FromExpression(tt.expression).Build()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			result := condition.EvaluateWithContext(ctx)
+			if result.Passed != tt.wantPassed || result.ActualValue != tt.wantActual || result.Description != tt.wantDescription {
+				t.Errorf("EvaluateWithContext() = %+v, want passed %v, actual %d, description %q", result, tt.wantPassed, tt.wantActual, tt.wantDescription)
+			}
+		})
+	}
+}
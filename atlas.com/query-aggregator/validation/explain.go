@@ -0,0 +1,113 @@
+package validation
+
+import (
+	"atlas-query-aggregator/character"
+	"time"
+
+	"github.com/Chronicle20/atlas-model/model"
+)
+
+// DataSource identifies the upstream resource supplying the actual value of a condition
+type DataSource string
+
+const (
+	CharactersSource    DataSource = "CHARACTERS"
+	InventorySource     DataSource = "INVENTORY"
+	GuildsSource        DataSource = "GUILDS"
+	QuestsSource        DataSource = "QUESTS"
+	MarriageSource      DataSource = "MARRIAGE"
+	ConfigurationSource DataSource = "CONFIGURATION" // Tenant configuration stored by this service
+)
+
+// Explanation describes where the actual value of a condition came from
+type Explanation struct {
+	Source     DataSource `json:"source"`          // Upstream resource supplying the value
+	Field      string     `json:"field"`           // Field of the upstream resource the value was read from
+	Fetched    bool       `json:"fetched"`         // Whether the resource was requested for this validation
+	Succeeded  bool       `json:"succeeded"`       // Whether the request succeeded; when false the value is a default
+	DurationMs float64    `json:"durationMs"`      // Time spent requesting the resource
+	Error      string     `json:"error,omitempty"` // Failure reported by the resource
+}
+
+// fetchReport records the outcome of requesting an upstream resource
+type fetchReport struct {
+	duration time.Duration
+	err      error
+}
+
+// fetchReports holds the outcome of each upstream request made for a validation, by source
+type fetchReports map[DataSource]fetchReport
+
+// decorator times a fallible character decoration, recording its outcome under the source.
+// The decorated model is returned as is, so a failed fetch leaves the model undecorated.
+func (r fetchReports) decorator(source DataSource, fetch func(character.Model) (character.Model, error)) model.Decorator[character.Model] {
+	return func(m character.Model) character.Model {
+		start := time.Now()
+		result, err := fetch(m)
+		r[source] = fetchReport{duration: time.Since(start), err: err}
+		return result
+	}
+}
+
+// total returns the combined duration of every recorded fetch
+func (r fetchReports) total() time.Duration {
+	var total time.Duration
+	for _, report := range r {
+		total += report.duration
+	}
+	return total
+}
+
+// source returns the upstream resource and field supplying the actual value of a non-group condition
+func (c Condition) source() (DataSource, string) {
+	definition, err := LookupConditionType(string(c.conditionType))
+	if err != nil {
+		return CharactersSource, string(c.conditionType)
+	}
+	return definition.sourceFor(c.referenceId, c.step)
+}
+
+// explain attaches an explanation to the result of the condition and of every nested condition
+func (c Condition) explain(result ConditionResult, reports fetchReports) ConditionResult {
+	if c.group != "" {
+		children := make([]ConditionResult, 0, len(result.Children))
+		for i, child := range c.children {
+			children = append(children, child.explain(result.Children[i], reports))
+		}
+		result.Children = children
+		return result
+	}
+
+	source, field := c.source()
+	explanation := &Explanation{Source: source, Field: field}
+	if report, ok := reports[source]; ok {
+		explanation.Fetched = true
+		explanation.Succeeded = report.err == nil
+		explanation.DurationMs = float64(report.duration.Microseconds()) / 1000
+		if report.err != nil {
+			explanation.Error = report.err.Error()
+		}
+	}
+	result.Explanation = explanation
+	return result
+}
+
+// withoutExplanations returns a copy of the results with every explanation removed
+func withoutExplanations(results []ConditionResult) []ConditionResult {
+	if results == nil {
+		return nil
+	}
+	stripped := make([]ConditionResult, 0, len(results))
+	for _, result := range results {
+		result.Explanation = nil
+		result.Children = withoutExplanations(result.Children)
+		stripped = append(stripped, result)
+	}
+	return stripped
+}
+
+// Explain is a validation result decorator that includes data provenance in the REST representation
+func Explain(v ValidationResult) ValidationResult {
+	v.explain = true
+	return v
+}
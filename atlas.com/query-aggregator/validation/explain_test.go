@@ -0,0 +1,158 @@
+package validation
+
+import (
+	"atlas-query-aggregator/character"
+	"atlas-query-aggregator/character/mock"
+	"atlas-query-aggregator/quest"
+	questMock "atlas-query-aggregator/quest/mock"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/sirupsen/logrus"
+)
+
+// TestProcessorValidateStructured_Explain tests that results record the source and fetch outcome of each value
+func TestProcessorValidateStructured_Explain(t *testing.T) {
+	logger := logrus.New()
+
+	mockCharProcessor := &mock.ProcessorImpl{
+		GetByIdFunc: func(decorators ...model.Decorator[character.Model]) func(characterId uint32) (character.Model, error) {
+			return func(characterId uint32) (character.Model, error) {
+				char := character.NewModelBuilder().SetId(characterId).SetLevel(50).Build()
+				for _, decorator := range decorators {
+					char = decorator(char)
+				}
+				return char, nil
+			}
+		},
+		WithInventoryFunc: func(m character.Model) (character.Model, error) {
+			return m, errors.New("inventory service unavailable")
+		},
+	}
+
+	processor := &ProcessorImpl{
+		l:                  logger,
+		ctx:                context.Background(),
+		characterProcessor: mockCharProcessor,
+		questProcessor: &questMock.ProcessorImpl{
+			GetQuestsFunc: func(characterId uint32) model.Provider[map[uint32]quest.Model] {
+				return func() (map[uint32]quest.Model, error) {
+					return nil, errors.New("quest service unavailable")
+				}
+			},
+		},
+	}
+
+	conditions := []ConditionInput{
+		{Type: "level", Operator: ">=", Value: 30},
+		{All: []ConditionInput{
+			{Type: "item", Operator: ">=", Value: 1, ReferenceId: 2000001},
+			{Type: "guildId", Operator: "=", Value: 1},
+		}},
+		{Type: "questStatus", Operator: "=", Value: 2, ReferenceId: 1001},
+	}
+
+	result, err := processor.ValidateStructured(Explain)(123, conditions)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		explanation   *Explanation
+		wantSource    DataSource
+		wantField     string
+		wantFetched   bool
+		wantSucceeded bool
+		wantError     string
+	}{
+		{
+			name:          "Character field",
+			explanation:   result.Results()[0].Explanation,
+			wantSource:    CharactersSource,
+			wantField:     "level",
+			wantFetched:   true,
+			wantSucceeded: true,
+		},
+		{
+			name:          "Failed inventory fetch",
+			explanation:   result.Results()[1].Children[0].Explanation,
+			wantSource:    InventorySource,
+			wantField:     "compartments.assets[templateId=2000001].quantity",
+			wantFetched:   true,
+			wantSucceeded: false,
+			wantError:     "inventory service unavailable",
+		},
+		{
+			name:          "Guild fetch",
+			explanation:   result.Results()[1].Children[1].Explanation,
+			wantSource:    GuildsSource,
+			wantField:     "guild.id",
+			wantFetched:   true,
+			wantSucceeded: true,
+		},
+		{
+			name:          "Failed quest fetch",
+			explanation:   result.Results()[2].Explanation,
+			wantSource:    QuestsSource,
+			wantField:     "quests[1001].status",
+			wantFetched:   true,
+			wantSucceeded: false,
+			wantError:     "quest service unavailable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := tt.explanation
+			if e == nil {
+				t.Fatalf("Expected explanation, got nil")
+			}
+			if e.Source != tt.wantSource || e.Field != tt.wantField {
+				t.Errorf("Source = %s %s, want %s %s", e.Source, e.Field, tt.wantSource, tt.wantField)
+			}
+			if e.Fetched != tt.wantFetched || e.Succeeded != tt.wantSucceeded {
+				t.Errorf("Fetched = %v, Succeeded = %v, want %v, %v", e.Fetched, e.Succeeded, tt.wantFetched, tt.wantSucceeded)
+			}
+			if e.Error != tt.wantError {
+				t.Errorf("Error = '%s', want '%s'", e.Error, tt.wantError)
+			}
+		})
+	}
+
+	if result.Results()[1].Explanation != nil {
+		t.Errorf("Expected no explanation on group result")
+	}
+}
+
+// TestTransform_Explain tests that explanations are only included in the REST model for explained results
+func TestTransform_Explain(t *testing.T) {
+	result := NewValidationResult(123)
+	result.AddConditionResult(ConditionResult{
+		Passed:      true,
+		Group:       AllGroup,
+		Children:    []ConditionResult{{Passed: true, Type: LevelCondition, Explanation: &Explanation{Source: CharactersSource, Field: "level"}}},
+		ActualValue: 1,
+	})
+
+	rm, err := Transform(result)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rm.Results[0].Children[0].Explanation != nil {
+		t.Errorf("Expected explanation to be omitted without explain mode")
+	}
+	if result.Results()[0].Children[0].Explanation == nil {
+		t.Errorf("Expected Transform to leave the domain result unchanged")
+	}
+
+	rm, err = Transform(Explain(result))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rm.Results[0].Children[0].Explanation == nil {
+		t.Errorf("Expected explanation to be included in explain mode")
+	}
+}
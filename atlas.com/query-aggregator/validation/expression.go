@@ -0,0 +1,440 @@
+package validation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Expressions are a compact textual form of ConditionInput trees, e.g.
+//
+//	level>=30 && (jobId=100 || jobId=110) && !item[4001000]>=1
+//	questProgress[1001:mobsKilled]>=5
+//	jobId in [100, 110, 120] && level between [30, 70]
+//
+// A comparison is written as type[referenceId:step] operator value, where the bracketed
+// reference and step are optional. The in and notIn operators take a bracketed list of
+// values, and between takes a bracketed [min, max] pair. Comparisons combine with
+// && (and), || (or) and ! (not), in increasing order of precedence, and may be grouped
+// with parentheses. The keywords and, or and not are accepted in place of the symbols.
+// A reference, step or single value may be written as a $name placeholder, e.g.
+//
+//	item[$itemId]>=$count
+
+// ExpressionError represents a syntax error in a condition expression
+type ExpressionError struct {
+	Position int // 1-based character position of the offending input
+	Message  string
+}
+
+// Error returns the error message including the position
+func (e ExpressionError) Error() string {
+	return fmt.Sprintf("expression error at position %d: %s", e.Position, e.Message)
+}
+
+// ParseExpression parses a condition expression into its structured ConditionInput form
+func ParseExpression(expression string) (ConditionInput, error) {
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return ConditionInput{}, err
+	}
+
+	p := &expressionParser{tokens: tokens}
+	input, err := p.parseOr()
+	if err != nil {
+		return ConditionInput{}, err
+	}
+	if t := p.peek(); t.kind != tokenEnd {
+		return ConditionInput{}, p.unexpected(t)
+	}
+	return input, nil
+}
+
+// resolveExpression parses the expression of an expression node, carrying over its weight
+func (i ConditionInput) resolveExpression() (ConditionInput, error) {
+	if i.Type != "" || i.Operator != "" || i.IsGroup() || i.Value != 0 || i.Values != nil || i.Min != nil || i.Max != nil || i.ReferenceId != 0 || i.Step != "" || i.ItemId != 0 || i.hasPlaceholders() {
+		return ConditionInput{}, fmt.Errorf("expression cannot be combined with other condition fields")
+	}
+	parsed, err := ParseExpression(i.Expression)
+	if err != nil {
+		return ConditionInput{}, err
+	}
+	parsed.Weight = i.Weight
+	return parsed, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenIdentifier
+	tokenNumber
+	tokenString
+	tokenParameter
+	tokenOperator
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenLeftParen
+	tokenRightParen
+	tokenLeftBracket
+	tokenRightBracket
+	tokenColon
+	tokenComma
+)
+
+type token struct {
+	kind     tokenKind
+	text     string
+	position int
+}
+
+// tokenize splits an expression into tokens, recording the 1-based position of each
+func tokenize(expression string) ([]token, error) {
+	runes := []rune(expression)
+	tokens := make([]token, 0)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		position := i + 1
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLeftParen, text: "(", position: position})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRightParen, text: ")", position: position})
+			i++
+		case r == '[':
+			tokens = append(tokens, token{kind: tokenLeftBracket, text: "[", position: position})
+			i++
+		case r == ']':
+			tokens = append(tokens, token{kind: tokenRightBracket, text: "]", position: position})
+			i++
+		case r == ':':
+			tokens = append(tokens, token{kind: tokenColon, text: ":", position: position})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", position: position})
+			i++
+		case r == '&' || r == '|':
+			if i+1 >= len(runes) || runes[i+1] != r {
+				return nil, ExpressionError{Position: position, Message: fmt.Sprintf("unexpected character '%c', did you mean '%c%c'", r, r, r)}
+			}
+			kind := tokenAnd
+			if r == '|' {
+				kind = tokenOr
+			}
+			tokens = append(tokens, token{kind: kind, text: string([]rune{r, r}), position: position})
+			i += 2
+		case r == '!' && (i+1 >= len(runes) || runes[i+1] != '='):
+			tokens = append(tokens, token{kind: tokenNot, text: "!", position: position})
+			i++
+		case r == '=' || r == '<' || r == '>' || r == '!':
+			start := i
+			for i < len(runes) && strings.ContainsRune("=<>!", runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: string(runes[start:i]), position: position})
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, ExpressionError{Position: position, Message: "unterminated string"}
+			}
+			tokens = append(tokens, token{kind: tokenString, text: string(runes[i+1 : end]), position: position})
+			i = end + 1
+		case r == '$':
+			start := i + 1
+			i++
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			if i == start {
+				return nil, ExpressionError{Position: position, Message: "expected parameter name after '$'"}
+			}
+			tokens = append(tokens, token{kind: tokenParameter, text: string(runes[start-1 : i]), position: position})
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			i++
+			for i < len(runes) && unicode.IsDigit(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[start:i]), position: position})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			text := string(runes[start:i])
+			kind := tokenIdentifier
+			switch strings.ToLower(text) {
+			case "and":
+				kind = tokenAnd
+			case "or":
+				kind = tokenOr
+			case "not":
+				kind = tokenNot
+			case strings.ToLower(string(In)), strings.ToLower(string(NotIn)), strings.ToLower(string(Between)):
+				kind = tokenOperator
+			}
+			tokens = append(tokens, token{kind: kind, text: text, position: position})
+		default:
+			return nil, ExpressionError{Position: position, Message: fmt.Sprintf("unexpected character '%c'", r)}
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokenEnd, position: len(runes) + 1})
+	return tokens, nil
+}
+
+// expressionParser is a recursive descent parser over expression tokens
+type expressionParser struct {
+	tokens []token
+	index  int
+}
+
+func (p *expressionParser) peek() token {
+	return p.tokens[p.index]
+}
+
+func (p *expressionParser) next() token {
+	t := p.tokens[p.index]
+	if t.kind != tokenEnd {
+		p.index++
+	}
+	return t
+}
+
+func (p *expressionParser) expect(kind tokenKind, description string) (token, error) {
+	t := p.next()
+	if t.kind != kind {
+		return t, ExpressionError{Position: t.position, Message: fmt.Sprintf("expected %s, found %s", description, describeToken(t))}
+	}
+	return t, nil
+}
+
+func (p *expressionParser) unexpected(t token) error {
+	return ExpressionError{Position: t.position, Message: fmt.Sprintf("unexpected %s", describeToken(t))}
+}
+
+// parseOr parses a disjunction, flattening chained connectives into a single any group
+func (p *expressionParser) parseOr() (ConditionInput, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return ConditionInput{}, err
+	}
+
+	members := []ConditionInput{first}
+	for p.peek().kind == tokenOr {
+		p.next()
+		member, err := p.parseAnd()
+		if err != nil {
+			return ConditionInput{}, err
+		}
+		members = append(members, member)
+	}
+
+	if len(members) == 1 {
+		return first, nil
+	}
+	return ConditionInput{Any: members}, nil
+}
+
+// parseAnd parses a conjunction, flattening chained connectives into a single all group
+func (p *expressionParser) parseAnd() (ConditionInput, error) {
+	first, err := p.parseUnary()
+	if err != nil {
+		return ConditionInput{}, err
+	}
+
+	members := []ConditionInput{first}
+	for p.peek().kind == tokenAnd {
+		p.next()
+		member, err := p.parseUnary()
+		if err != nil {
+			return ConditionInput{}, err
+		}
+		members = append(members, member)
+	}
+
+	if len(members) == 1 {
+		return first, nil
+	}
+	return ConditionInput{All: members}, nil
+}
+
+// parseUnary parses a negation, parenthesized expression or comparison
+func (p *expressionParser) parseUnary() (ConditionInput, error) {
+	switch p.peek().kind {
+	case tokenNot:
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return ConditionInput{}, err
+		}
+		return ConditionInput{Not: &operand}, nil
+	case tokenLeftParen:
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return ConditionInput{}, err
+		}
+		if _, err := p.expect(tokenRightParen, "')'"); err != nil {
+			return ConditionInput{}, err
+		}
+		return inner, nil
+	default:
+		return p.parseComparison()
+	}
+}
+
+// parseComparison parses type[referenceId:step] operator value
+func (p *expressionParser) parseComparison() (ConditionInput, error) {
+	typeToken, err := p.expect(tokenIdentifier, "condition type")
+	if err != nil {
+		return ConditionInput{}, err
+	}
+	input := ConditionInput{Type: typeToken.text}
+
+	if p.peek().kind == tokenLeftBracket {
+		p.next()
+		if p.peek().kind == tokenParameter {
+			input.ReferenceIdParam = parameterName(p.next())
+		} else {
+			referenceToken, err := p.expect(tokenNumber, "reference id")
+			if err != nil {
+				return ConditionInput{}, err
+			}
+			referenceId, err := strconv.ParseUint(referenceToken.text, 10, 32)
+			if err != nil {
+				return ConditionInput{}, ExpressionError{Position: referenceToken.position, Message: fmt.Sprintf("invalid reference id %s", referenceToken.text)}
+			}
+			input.ReferenceId = uint32(referenceId)
+		}
+
+		if p.peek().kind == tokenColon {
+			p.next()
+			stepToken := p.next()
+			switch stepToken.kind {
+			case tokenIdentifier, tokenNumber, tokenString:
+				input.Step = stepToken.text
+			case tokenParameter:
+				input.StepParam = parameterName(stepToken)
+			default:
+				return ConditionInput{}, ExpressionError{Position: stepToken.position, Message: fmt.Sprintf("expected step, found %s", describeToken(stepToken))}
+			}
+		}
+
+		if _, err := p.expect(tokenRightBracket, "']'"); err != nil {
+			return ConditionInput{}, err
+		}
+	}
+
+	operatorToken, err := p.expect(tokenOperator, "comparison operator")
+	if err != nil {
+		return ConditionInput{}, err
+	}
+	operator, err := parseExpressionOperator(operatorToken.text)
+	if err != nil {
+		return ConditionInput{}, ExpressionError{Position: operatorToken.position, Message: fmt.Sprintf("unsupported operator %s", operatorToken.text)}
+	}
+	input.Operator = string(operator)
+
+	switch {
+	case operator.IsSet():
+		values, _, err := p.parseValueList()
+		if err != nil {
+			return ConditionInput{}, err
+		}
+		input.Values = values
+	case operator == Between:
+		bounds, open, err := p.parseValueList()
+		if err != nil {
+			return ConditionInput{}, err
+		}
+		if len(bounds) != 2 {
+			return ConditionInput{}, ExpressionError{Position: open.position, Message: "between requires exactly two values [min, max]"}
+		}
+		input.Min = &bounds[0]
+		input.Max = &bounds[1]
+	case p.peek().kind == tokenParameter:
+		input.ValueParam = parameterName(p.next())
+	default:
+		value, err := p.parseValue()
+		if err != nil {
+			return ConditionInput{}, err
+		}
+		input.Value = value
+	}
+
+	return input, nil
+}
+
+// parseValue parses a single numeric value
+func (p *expressionParser) parseValue() (int, error) {
+	valueToken, err := p.expect(tokenNumber, "numeric value")
+	if err != nil {
+		return 0, err
+	}
+	value, err := strconv.Atoi(valueToken.text)
+	if err != nil {
+		return 0, ExpressionError{Position: valueToken.position, Message: fmt.Sprintf("invalid value %s", valueToken.text)}
+	}
+	return value, nil
+}
+
+// parseValueList parses a bracketed, comma separated list of numeric values, returning the opening bracket
+func (p *expressionParser) parseValueList() ([]int, token, error) {
+	open, err := p.expect(tokenLeftBracket, "'['")
+	if err != nil {
+		return nil, open, err
+	}
+
+	values := make([]int, 0)
+	for {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, open, err
+		}
+		values = append(values, value)
+
+		if p.peek().kind != tokenComma {
+			break
+		}
+		p.next()
+	}
+
+	if _, err := p.expect(tokenRightBracket, "']'"); err != nil {
+		return nil, open, err
+	}
+	return values, open, nil
+}
+
+// parameterName returns the name of a placeholder token without its leading '$'
+func parameterName(t token) string {
+	return strings.TrimPrefix(t.text, "$")
+}
+
+// parseExpressionOperator resolves an operator token, accepting the word operators in any case
+func parseExpressionOperator(text string) (Operator, error) {
+	for _, o := range operators {
+		if strings.EqualFold(string(o), text) {
+			return o, nil
+		}
+	}
+	return ParseOperator(text)
+}
+
+// describeToken returns a human readable description of a token for error messages
+func describeToken(t token) string {
+	if t.kind == tokenEnd {
+		return "end of expression"
+	}
+	return fmt.Sprintf("'%s'", t.text)
+}
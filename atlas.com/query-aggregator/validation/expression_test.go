@@ -0,0 +1,303 @@
+package validation
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestParseExpression tests parsing of compact condition expressions
+func TestParseExpression(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		want       ConditionInput
+	}{
+		{
+			name:       "Simple comparison",
+			expression: "jobId=100",
+			want:       ConditionInput{Type: "jobId", Operator: "=", Value: 100},
+		},
+		{
+			name:       "Comparison with whitespace",
+			expression: "  meso >= 10000 ",
+			want:       ConditionInput{Type: "meso", Operator: ">=", Value: 10000},
+		},
+		{
+			name:       "Negative value",
+			expression: "fame>-5",
+			want:       ConditionInput{Type: "fame", Operator: ">", Value: -5},
+		},
+		{
+			name:       "Item with reference",
+			expression: "item[2000001]>=10",
+			want:       ConditionInput{Type: "item", Operator: ">=", Value: 10, ReferenceId: 2000001},
+		},
+		{
+			name:       "Placeholders",
+			expression: "questProgress[$questId:$step]>=$count",
+			want:       ConditionInput{Type: "questProgress", Operator: ">=", ReferenceIdParam: "questId", StepParam: "step", ValueParam: "count"},
+		},
+		{
+			name:       "Quest progress with reference and step",
+			expression: "questProgress[1001:mobsKilled]>=5",
+			want:       ConditionInput{Type: "questProgress", Operator: ">=", Value: 5, ReferenceId: 1001, Step: "mobsKilled"},
+		},
+		{
+			name:       "Quoted step",
+			expression: `questProgress[1001:"kill count"]>=5`,
+			want:       ConditionInput{Type: "questProgress", Operator: ">=", Value: 5, ReferenceId: 1001, Step: "kill count"},
+		},
+		{
+			name:       "Chained and flattens into one group",
+			expression: "level>=30 && jobId=100 && meso>0",
+			want: ConditionInput{All: []ConditionInput{
+				{Type: "level", Operator: ">=", Value: 30},
+				{Type: "jobId", Operator: "=", Value: 100},
+				{Type: "meso", Operator: ">", Value: 0},
+			}},
+		},
+		{
+			name:       "And binds tighter than or",
+			expression: "jobId=100 || jobId=110 && level>=30",
+			want: ConditionInput{Any: []ConditionInput{
+				{Type: "jobId", Operator: "=", Value: 100},
+				{All: []ConditionInput{
+					{Type: "jobId", Operator: "=", Value: 110},
+					{Type: "level", Operator: ">=", Value: 30},
+				}},
+			}},
+		},
+		{
+			name:       "Parentheses override precedence",
+			expression: "(jobId=100 || jobId=110) && level>=30",
+			want: ConditionInput{All: []ConditionInput{
+				{Any: []ConditionInput{
+					{Type: "jobId", Operator: "=", Value: 100},
+					{Type: "jobId", Operator: "=", Value: 110},
+				}},
+				{Type: "level", Operator: ">=", Value: 30},
+			}},
+		},
+		{
+			name:       "Negation",
+			expression: "!item[4001000]>=1",
+			want: ConditionInput{Not: &ConditionInput{
+				Type: "item", Operator: ">=", Value: 1, ReferenceId: 4001000,
+			}},
+		},
+		{
+			name:       "Not equals",
+			expression: "mapId!=910000000",
+			want:       ConditionInput{Type: "mapId", Operator: "!=", Value: 910000000},
+		},
+		{
+			name:       "In list",
+			expression: "jobId in [100, 110, 120,130]",
+			want:       ConditionInput{Type: "jobId", Operator: "in", Values: []int{100, 110, 120, 130}},
+		},
+		{
+			name:       "Not in list",
+			expression: "jobId notIn [900]",
+			want:       ConditionInput{Type: "jobId", Operator: "notIn", Values: []int{900}},
+		},
+		{
+			name:       "Between range",
+			expression: "level between [30, 70] && !mapId!=100000000",
+			want: ConditionInput{All: []ConditionInput{
+				{Type: "level", Operator: "between", Min: intPtr(30), Max: intPtr(70)},
+				{Not: &ConditionInput{Type: "mapId", Operator: "!=", Value: 100000000}},
+			}},
+		},
+		{
+			name:       "Keyword connectives",
+			expression: "level>=30 AND not (gender=1 or fame<0)",
+			want: ConditionInput{All: []ConditionInput{
+				{Type: "level", Operator: ">=", Value: 30},
+				{Not: &ConditionInput{Any: []ConditionInput{
+					{Type: "gender", Operator: "=", Value: 1},
+					{Type: "fame", Operator: "<", Value: 0},
+				}}},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseExpression(tt.expression)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseExpression() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseExpression_Errors tests that syntax errors report their position
+func TestParseExpression_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		expression    string
+		wantPosition  int
+		errorContains string
+	}{
+		{
+			name:          "Empty expression",
+			expression:    "",
+			wantPosition:  1,
+			errorContains: "expected condition type, found end of expression",
+		},
+		{
+			name:          "Missing operator",
+			expression:    "level 30",
+			wantPosition:  7,
+			errorContains: "expected comparison operator, found '30'",
+		},
+		{
+			name:          "Placeholder without name",
+			expression:    "level>=$",
+			wantPosition:  8,
+			errorContains: "expected parameter name after '$'",
+		},
+		{
+			name:          "Placeholder in value list",
+			expression:    "jobId in [100, $job]",
+			wantPosition:  16,
+			errorContains: "expected numeric value, found '$job'",
+		},
+		{
+			name:          "Unsupported operator",
+			expression:    "level=>30",
+			wantPosition:  6,
+			errorContains: "unsupported operator =>",
+		},
+		{
+			name:          "Missing value",
+			expression:    "level>=",
+			wantPosition:  8,
+			errorContains: "expected numeric value, found end of expression",
+		},
+		{
+			name:          "Unclosed bracket",
+			expression:    "item[2000001>=10",
+			wantPosition:  13,
+			errorContains: "expected ']'",
+		},
+		{
+			name:          "Unclosed parenthesis",
+			expression:    "(level>=30 || jobId=100",
+			wantPosition:  24,
+			errorContains: "expected ')'",
+		},
+		{
+			name:          "Single ampersand",
+			expression:    "level>=30 & jobId=100",
+			wantPosition:  11,
+			errorContains: "did you mean '&&'",
+		},
+		{
+			name:          "Trailing tokens",
+			expression:    "level>=30 jobId=100",
+			wantPosition:  11,
+			errorContains: "unexpected 'jobId'",
+		},
+		{
+			name:          "Reference id out of range",
+			expression:    "item[99999999999]>=1",
+			wantPosition:  6,
+			errorContains: "invalid reference id",
+		},
+		{
+			name:          "In without list",
+			expression:    "jobId in 100",
+			wantPosition:  10,
+			errorContains: "expected '['",
+		},
+		{
+			name:          "Between with one bound",
+			expression:    "level between [30]",
+			wantPosition:  15,
+			errorContains: "between requires exactly two values",
+		},
+		{
+			name:          "Unexpected character",
+			expression:    "level>=30 ; jobId=100",
+			wantPosition:  11,
+			errorContains: "unexpected character ';'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseExpression(tt.expression)
+			if err == nil {
+				t.Fatalf("Expected error, got nil")
+			}
+			var expressionErr ExpressionError
+			if !errors.As(err, &expressionErr) {
+				t.Fatalf("Expected ExpressionError, got %T", err)
+			}
+			if expressionErr.Position != tt.wantPosition {
+				t.Errorf("Position = %d, want %d", expressionErr.Position, tt.wantPosition)
+			}
+			if !strings.Contains(err.Error(), tt.errorContains) {
+				t.Errorf("Expected error containing '%s', got '%v'", tt.errorContains, err)
+			}
+		})
+	}
+}
+
+// TestConditionBuilder_FromExpression tests that expressions compile to the same conditions as structured input
+func TestConditionBuilder_FromExpression(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		input      ConditionInput
+	}{
+		{
+			name:       "Quest progress",
+			expression: "questProgress[1001:mobsKilled]>=5",
+			input:      ConditionInput{Type: "questProgress", Operator: ">=", Value: 5, ReferenceId: 1001, Step: "mobsKilled"},
+		},
+		{
+			name:       "Grouped conditions",
+			expression: "level>=30 && (jobId=100 || !item[4001000]>=1)",
+			input: ConditionInput{All: []ConditionInput{
+				{Type: "level", Operator: ">=", Value: 30},
+				{Any: []ConditionInput{
+					{Type: "jobId", Operator: "=", Value: 100},
+					{Not: &ConditionInput{Type: "item", Operator: ">=", Value: 1, ReferenceId: 4001000}},
+				}},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fromExpression, err := NewConditionBuilder().FromExpression(tt.expression).Build()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			fromInput, err := NewConditionBuilder().FromInput(tt.input).Build()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(fromExpression, fromInput) {
+				t.Errorf("FromExpression() = %+v, want %+v", fromExpression, fromInput)
+			}
+		})
+	}
+
+	// Semantic errors surface from the builder once the expression parses
+	if _, err := NewConditionBuilder().FromExpression("unknown>=1").Build(); err == nil || !strings.Contains(err.Error(), "unsupported condition type") {
+		t.Errorf("Expected unsupported condition type error, got %v", err)
+	}
+
+	// Expression nodes cannot be mixed with structured fields
+	if err := validateConditionInput(ConditionInput{Expression: "level>=30", Type: "level"}); err == nil {
+		t.Errorf("Expected error for expression combined with other fields, got nil")
+	}
+}
@@ -2,12 +2,14 @@ package mock
 
 import (
 	"atlas-query-aggregator/validation"
+	"github.com/Chronicle20/atlas-constants/world"
 	"github.com/Chronicle20/atlas-model/model"
 )
 
 // ProcessorImpl is a mock implementation of the validation.ProcessorImpl
 type ProcessorImpl struct {
 	ValidateStructuredFunc func(decorators ...model.Decorator[validation.ValidationResult]) func(characterId uint32, conditionInputs []validation.ConditionInput) (validation.ValidationResult, error)
+	ValidateAccountFunc    func(decorators ...model.Decorator[validation.ValidationResult]) func(accountId uint32, worldId world.Id, scope validation.AccountScope, conditionInputs []validation.ConditionInput) (validation.AccountValidationResult, error)
 }
 
 // ValidateStructured returns a function that validates structured conditions against a character
@@ -19,3 +21,13 @@ func (m *ProcessorImpl) ValidateStructured(decorators ...model.Decorator[validat
 		return validation.NewValidationResult(characterId), nil
 	}
 }
+
+// ValidateAccount returns a function that validates structured conditions against every character an account has in a world
+func (m *ProcessorImpl) ValidateAccount(decorators ...model.Decorator[validation.ValidationResult]) func(accountId uint32, worldId world.Id, scope validation.AccountScope, conditionInputs []validation.ConditionInput) (validation.AccountValidationResult, error) {
+	if m.ValidateAccountFunc != nil {
+		return m.ValidateAccountFunc(decorators...)
+	}
+	return func(accountId uint32, worldId world.Id, scope validation.AccountScope, conditionInputs []validation.ConditionInput) (validation.AccountValidationResult, error) {
+		return validation.NewAccountValidationResult(accountId, scope, nil), nil
+	}
+}
@@ -0,0 +1,110 @@
+package validation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Chronicle20/atlas-model/model"
+)
+
+// ModeType represents how the results of the top-level conditions combine into the overall outcome
+type ModeType string
+
+const (
+	AllMode     ModeType = "all"     // Passes when every condition passes
+	AnyMode     ModeType = "any"     // Passes when at least one condition passes
+	AtLeastMode ModeType = "atLeast" // Passes when at least N conditions pass, written atLeast:N
+	ScoreMode   ModeType = "score"   // Passes when the summed weight of passing conditions reaches the threshold
+)
+
+// Mode describes how a validation combines the results of its top-level conditions
+type Mode struct {
+	modeType  ModeType
+	count     int // Required number of passing conditions for the atLeast mode
+	threshold int // Required score for the score mode
+}
+
+// ParseMode parses a validation mode. An empty mode is the all mode. The threshold is required by,
+// and only supported by, the score mode.
+func ParseMode(mode string, threshold *int) (Mode, error) {
+	name, argument, hasArgument := strings.Cut(mode, ":")
+	m := Mode{modeType: ModeType(name)}
+	if name == "" {
+		m.modeType = AllMode
+	}
+
+	switch m.modeType {
+	case AllMode, AnyMode, ScoreMode:
+		if hasArgument {
+			return Mode{}, fmt.Errorf("mode %s does not take an argument", m.modeType)
+		}
+	case AtLeastMode:
+		count, err := strconv.Atoi(argument)
+		if !hasArgument || err != nil || count < 1 {
+			return Mode{}, fmt.Errorf("mode atLeast requires a positive count, e.g. atLeast:3")
+		}
+		m.count = count
+	default:
+		return Mode{}, fmt.Errorf("unsupported mode: %s", mode)
+	}
+
+	if m.modeType == ScoreMode {
+		if threshold == nil {
+			return Mode{}, fmt.Errorf("threshold is required for score mode")
+		}
+		m.threshold = *threshold
+	} else if threshold != nil {
+		return Mode{}, fmt.Errorf("threshold is only supported by score mode")
+	}
+	return m, nil
+}
+
+// Type returns the mode type
+func (m Mode) Type() ModeType {
+	return m.modeType
+}
+
+// Count returns the number of conditions required to pass in atLeast mode
+func (m Mode) Count() int {
+	return m.count
+}
+
+// Threshold returns the score required to pass in score mode
+func (m Mode) Threshold() int {
+	return m.threshold
+}
+
+// String returns the mode in its request form, e.g. "atLeast:3"
+func (m Mode) String() string {
+	if m.modeType == AtLeastMode {
+		return fmt.Sprintf("%s:%d", m.modeType, m.count)
+	}
+	return string(m.modeType)
+}
+
+// validateFor checks that the mode can be satisfied by the provided number of conditions
+func (m Mode) validateFor(conditionCount int) error {
+	if m.modeType == AtLeastMode && m.count > conditionCount {
+		return fmt.Errorf("mode %s requires more conditions than the %d provided", m, conditionCount)
+	}
+	return nil
+}
+
+// ModeDecorator returns a validation result decorator which decides the outcome using the provided mode
+func ModeDecorator(mode Mode) model.Decorator[ValidationResult] {
+	return func(v ValidationResult) ValidationResult {
+		v.mode = mode
+		switch mode.modeType {
+		case AnyMode:
+			v.passed = v.passedCount > 0
+		case AtLeastMode:
+			v.passed = v.passedCount >= mode.count
+		case ScoreMode:
+			v.passed = v.score >= mode.threshold
+		default:
+			v.passed = v.passedCount == len(v.results)
+		}
+		return v
+	}
+}
@@ -0,0 +1,170 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestParseMode tests parsing of validation modes
+func TestParseMode(t *testing.T) {
+	threshold := 5
+
+	tests := []struct {
+		name          string
+		mode          string
+		threshold     *int
+		wantType      ModeType
+		wantCount     int
+		errorContains string
+	}{
+		{name: "Default", mode: "", wantType: AllMode},
+		{name: "All", mode: "all", wantType: AllMode},
+		{name: "Any", mode: "any", wantType: AnyMode},
+		{name: "At least", mode: "atLeast:3", wantType: AtLeastMode, wantCount: 3},
+		{name: "Score", mode: "score", threshold: &threshold, wantType: ScoreMode},
+		{name: "At least without count", mode: "atLeast", errorContains: "mode atLeast requires a positive count"},
+		{name: "At least with zero count", mode: "atLeast:0", errorContains: "mode atLeast requires a positive count"},
+		{name: "Argument for any", mode: "any:2", errorContains: "mode any does not take an argument"},
+		{name: "Score without threshold", mode: "score", errorContains: "threshold is required for score mode"},
+		{name: "Threshold without score", mode: "all", threshold: &threshold, errorContains: "threshold is only supported by score mode"},
+		{name: "Unknown mode", mode: "most", errorContains: "unsupported mode: most"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := ParseMode(tt.mode, tt.threshold)
+			if tt.errorContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errorContains) {
+					t.Errorf("Expected error containing '%s', got '%v'", tt.errorContains, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if m.Type() != tt.wantType || m.Count() != tt.wantCount {
+				t.Errorf("ParseMode() = %s count %d, want %s count %d", m.Type(), m.Count(), tt.wantType, tt.wantCount)
+			}
+		})
+	}
+}
+
+// TestModeDecorator tests how each mode combines condition results into the outcome
+func TestModeDecorator(t *testing.T) {
+	// Three of five conditions pass, scoring 3 + 1 + 2 = 6 of a possible 10
+	result := NewValidationResult(123)
+	result.AddWeightedConditionResult(ConditionResult{Passed: true, Description: "a"}, 3)
+	result.AddWeightedConditionResult(ConditionResult{Passed: false, Description: "b"}, 2)
+	result.AddWeightedConditionResult(ConditionResult{Passed: true, Description: "c"}, 1)
+	result.AddWeightedConditionResult(ConditionResult{Passed: true, Description: "d"}, 2)
+	result.AddWeightedConditionResult(ConditionResult{Passed: false, Description: "e"}, 2)
+
+	if result.PassedCount() != 3 || result.TotalCount() != 5 || result.Score() != 6 {
+		t.Fatalf("Tally = %d of %d scoring %d, want 3 of 5 scoring 6", result.PassedCount(), result.TotalCount(), result.Score())
+	}
+
+	tests := []struct {
+		name       string
+		mode       Mode
+		wantPassed bool
+	}{
+		{name: "All", mode: Mode{modeType: AllMode}, wantPassed: false},
+		{name: "Any", mode: Mode{modeType: AnyMode}, wantPassed: true},
+		{name: "At least 3", mode: Mode{modeType: AtLeastMode, count: 3}, wantPassed: true},
+		{name: "At least 4", mode: Mode{modeType: AtLeastMode, count: 4}, wantPassed: false},
+		{name: "Score reaching threshold", mode: Mode{modeType: ScoreMode, threshold: 6}, wantPassed: true},
+		{name: "Score below threshold", mode: Mode{modeType: ScoreMode, threshold: 7}, wantPassed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decorated := ModeDecorator(tt.mode)(result)
+			if decorated.Passed() != tt.wantPassed {
+				t.Errorf("Passed() = %v, want %v", decorated.Passed(), tt.wantPassed)
+			}
+			if len(decorated.Results()) != 5 {
+				t.Errorf("Expected all condition results to be retained, got %d", len(decorated.Results()))
+			}
+		})
+	}
+}
+
+// TestTransform_Mode tests that the tally and score are reported alongside the condition results
+func TestTransform_Mode(t *testing.T) {
+	result := NewValidationResult(123)
+	result.AddWeightedConditionResult(ConditionResult{Passed: true}, 4)
+	result.AddWeightedConditionResult(ConditionResult{Passed: false}, 1)
+
+	rm, err := Transform(ModeDecorator(Mode{modeType: ScoreMode, threshold: 4})(result))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !rm.Passed || rm.Mode != "score" || rm.PassedCount != 1 || rm.TotalCount != 2 {
+		t.Errorf("Unexpected REST model %+v", rm)
+	}
+	if rm.Score == nil || *rm.Score != 4 || rm.Threshold == nil || *rm.Threshold != 4 {
+		t.Errorf("Expected score 4 and threshold 4, got %v and %v", rm.Score, rm.Threshold)
+	}
+
+	rm, err = Transform(ModeDecorator(Mode{modeType: AtLeastMode, count: 1})(result))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rm.Mode != "atLeast:1" || rm.Score != nil {
+		t.Errorf("Expected atLeast:1 without score, got %s with %v", rm.Mode, rm.Score)
+	}
+}
+
+// TestConditionBuilder_Weight tests weights on structured and expression conditions
+func TestConditionBuilder_Weight(t *testing.T) {
+	weight := 3
+	zero := 0
+
+	condition, err := NewConditionBuilder().FromInput(ConditionInput{Type: "level", Operator: ">=", Value: 10, Weight: &weight}).Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if condition.Weight() != 3 {
+		t.Errorf("Weight() = %d, want 3", condition.Weight())
+	}
+
+	condition, err = NewConditionBuilder().FromInput(ConditionInput{Expression: "level>=10 && fame>=5", Weight: &weight}).Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if condition.Weight() != 3 {
+		t.Errorf("Expression Weight() = %d, want 3", condition.Weight())
+	}
+
+	condition, err = NewConditionBuilder().FromInput(ConditionInput{Type: "level", Operator: ">=", Value: 10}).Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if condition.Weight() != 1 {
+		t.Errorf("Default Weight() = %d, want 1", condition.Weight())
+	}
+
+	invalid := ConditionInput{Type: "level", Operator: ">=", Value: 10, Weight: &zero}
+	if _, err := NewConditionBuilder().FromInput(invalid).Build(); err == nil || !strings.Contains(err.Error(), "weight must be positive") {
+		t.Errorf("Expected weight error from builder, got %v", err)
+	}
+	if err := validateConditionInput(invalid); err == nil || !strings.Contains(err.Error(), "weight must be positive") {
+		t.Errorf("Expected weight error from validateConditionInput, got %v", err)
+	}
+}
+
+// TestExtractMode tests that a quorum cannot exceed the number of conditions
+func TestExtractMode(t *testing.T) {
+	rm := RestModel{
+		Mode:       "atLeast:3",
+		Conditions: []ConditionInput{{Type: "level", Operator: ">=", Value: 10}, {Type: "fame", Operator: ">=", Value: 5}},
+	}
+	if _, err := ExtractMode(rm); err == nil || !strings.Contains(err.Error(), "mode atLeast:3 requires more conditions than the 2 provided") {
+		t.Errorf("Expected quorum error, got %v", err)
+	}
+
+	rm.Mode = "atLeast:2"
+	if _, err := ExtractMode(rm); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
@@ -2,11 +2,11 @@ package validation
 
 import (
 	"atlas-query-aggregator/character"
-	"atlas-query-aggregator/quest"
 	"fmt"
-
-	inventory2 "github.com/Chronicle20/atlas-constants/inventory"
-	"github.com/Chronicle20/atlas-constants/item"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // ConditionType represents the type of condition to validate
@@ -27,13 +27,76 @@ const (
 	GuildIdCondition                ConditionType = "guildId"
 	GuildLeaderCondition            ConditionType = "guildLeader"
 	GuildRankCondition              ConditionType = "guildRank"
+	GuildPointsCondition            ConditionType = "guildPoints"
+	GuildCapacityCondition          ConditionType = "guildCapacity"
+	GuildMemberCountCondition       ConditionType = "guildMemberCount"
+	GuildOnlineMemberCountCondition ConditionType = "guildOnlineMemberCount"
+	GuildMembersAtLevelCondition    ConditionType = "guildMembersAtLevel"
+	GuildMembersAtRankCondition     ConditionType = "guildMembersAtRank"
 	QuestStatusCondition            ConditionType = "questStatus"
 	QuestProgressCondition          ConditionType = "questProgress"
+	QuestCompletedCountCondition    ConditionType = "questCompletedCount"
+	QuestCompletedWithinCondition   ConditionType = "questCompletedWithin"
+	QuestCompletedBeforeCondition   ConditionType = "questCompletedBefore"
+	QuestStartedWithinCondition     ConditionType = "questStartedWithin"
+	QuestForfeitCountCondition      ConditionType = "questForfeitCount"
 	UnclaimedMarriageGiftsCondition ConditionType = "hasUnclaimedMarriageGifts"
 	StrengthCondition               ConditionType = "strength"
 	DexterityCondition              ConditionType = "dexterity"
 	IntelligenceCondition           ConditionType = "intelligence"
 	LuckCondition                   ConditionType = "luck"
+	EquippedCondition               ConditionType = "equipped"
+	ItemOwnedCondition              ConditionType = "itemOwned"
+	ItemExpiringWithinCondition     ConditionType = "itemExpiringWithin"
+	TotalStrengthCondition          ConditionType = "totalStrength"
+	TotalDexterityCondition         ConditionType = "totalDexterity"
+	TotalIntelligenceCondition      ConditionType = "totalIntelligence"
+	TotalLuckCondition              ConditionType = "totalLuck"
+	TotalHpCondition                ConditionType = "totalHp"
+	TotalMpCondition                ConditionType = "totalMp"
+	TotalWeaponAttackCondition      ConditionType = "totalWeaponAttack"
+	TotalMagicAttackCondition       ConditionType = "totalMagicAttack"
+	TotalWeaponDefenseCondition     ConditionType = "totalWeaponDefense"
+	TotalMagicDefenseCondition      ConditionType = "totalMagicDefense"
+	TotalAccuracyCondition          ConditionType = "totalAccuracy"
+	TotalAvoidabilityCondition      ConditionType = "totalAvoidability"
+	TotalSpeedCondition             ConditionType = "totalSpeed"
+	TotalJumpCondition              ConditionType = "totalJump"
+	FreeSlotsCondition              ConditionType = "freeSlots"
+	JobBranchCondition              ConditionType = "jobBranch"
+	JobAdvancementCondition         ConditionType = "jobAdvancement"
+	JobFamilyCondition              ConditionType = "jobFamily"
+	HpCondition                     ConditionType = "hp"
+	MaxHpCondition                  ConditionType = "maxHp"
+	HpPercentCondition              ConditionType = "hpPercent"
+	MpCondition                     ConditionType = "mp"
+	MaxMpCondition                  ConditionType = "maxMp"
+	MpPercentCondition              ConditionType = "mpPercent"
+	ApCondition                     ConditionType = "ap"
+	HpMpUsedCondition               ConditionType = "hpMpUsed"
+	RemainingSpCondition            ConditionType = "remainingSp"
+	ExperienceCondition             ConditionType = "experience"
+	ExperiencePercentCondition      ConditionType = "experiencePercent"
+	GachaponExperienceCondition     ConditionType = "gachaponExperience"
+	PetOwnedCondition               ConditionType = "petOwned"
+	PetsSummonedCondition           ConditionType = "petsSummoned"
+	PetLevelCondition               ConditionType = "petLevel"
+	PetClosenessCondition           ConditionType = "petCloseness"
+	PetFullnessCondition            ConditionType = "petFullness"
+	HairCondition                   ConditionType = "hair"
+	HairStyleCondition              ConditionType = "hairStyle"
+	HairColorCondition              ConditionType = "hairColor"
+	FaceCondition                   ConditionType = "face"
+	FaceStyleCondition              ConditionType = "faceStyle"
+	FaceColorCondition              ConditionType = "faceColor"
+	SkinColorCondition              ConditionType = "skinColor"
+	MapRegionCondition              ConditionType = "mapRegion"
+	MapContinentCondition           ConditionType = "mapContinent"
+	MapInCondition                  ConditionType = "mapIn"
+	DateRangeCondition              ConditionType = "dateRange"
+	MonthDayCondition               ConditionType = "monthDay"
+	DayOfWeekCondition              ConditionType = "dayOfWeek"
+	HourRangeCondition              ConditionType = "hourRange"
 )
 
 // Operator represents the comparison operator in a condition
@@ -45,6 +108,67 @@ const (
 	LessThan     Operator = "<"
 	GreaterEqual Operator = ">="
 	LessEqual    Operator = "<="
+	NotEquals    Operator = "!="
+	In           Operator = "in"      // Actual value is one of the condition values
+	NotIn        Operator = "notIn"   // Actual value is none of the condition values
+	Between      Operator = "between" // Actual value lies within min and max, inclusive
+)
+
+// operators lists every supported operator
+var operators = []Operator{Equals, NotEquals, GreaterThan, LessThan, GreaterEqual, LessEqual, In, NotIn, Between}
+
+// ParseOperator returns the supported operator matching the provided string
+func ParseOperator(op string) (Operator, error) {
+	for _, o := range operators {
+		if string(o) == op {
+			return o, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported operator: %s", op)
+}
+
+// IsSet returns whether the operator compares against a set of values rather than a single value
+func (o Operator) IsSet() bool {
+	return o == In || o == NotIn
+}
+
+// validateOperands checks that the operands supplied with an operator match what it compares against and the condition
+// type. A step may name a tenant configured list in place of values, and a range may wrap when the type allows it.
+func validateOperands(d ConditionTypeDefinition, op Operator, values []int, min *int, max *int, hasStep bool) error {
+	switch {
+	case op.IsSet() && d.StepValues() != "" && hasStep:
+		if values != nil {
+			return fmt.Errorf("values cannot be combined with a named list of values")
+		}
+	case op.IsSet():
+		if len(values) == 0 {
+			return fmt.Errorf("values are required for %s conditions", op)
+		}
+	case values != nil:
+		return fmt.Errorf("values are only supported by the in and notIn operators")
+	}
+
+	switch {
+	case op == Between:
+		if min == nil || max == nil {
+			return fmt.Errorf("min and max are required for between conditions")
+		}
+		if *min > *max && !d.Wraps() {
+			return fmt.Errorf("min must not be greater than max")
+		}
+	case min != nil || max != nil:
+		return fmt.Errorf("min and max are only supported by the between operator")
+	}
+	return nil
+}
+
+// GroupType represents the logical connective of a condition group
+type GroupType string
+
+const (
+	AllGroup GroupType = "all" // Passes when every member passes
+	AnyGroup GroupType = "any" // Passes when at least one member passes
+	NotGroup GroupType = "not" // Passes when its single member fails
 )
 
 // ConditionInput represents the structured input for creating a condition
@@ -52,20 +176,83 @@ type ConditionInput struct {
 	Type        string `json:"type"`                  // e.g., "jobId", "meso", "item"
 	Operator    string `json:"operator"`              // e.g., "=", ">=", "<"
 	Value       int    `json:"value"`                 // Value or quantity
+	Values      []int  `json:"values,omitempty"`      // Candidate values for the in and notIn operators
+	Min         *int   `json:"min,omitempty"`         // Inclusive lower bound for the between operator
+	Max         *int   `json:"max,omitempty"`         // Inclusive upper bound for the between operator
 	ReferenceId uint32 `json:"referenceId,omitempty"` // For quest validation, item checks, etc.
 	Step        string `json:"step,omitempty"`        // For quest progress validation
 	ItemId      uint32 `json:"itemId,omitempty"`      // Deprecated: use ReferenceId instead
+	Weight      *int   `json:"weight,omitempty"`      // Score contributed when passing in score mode, defaults to 1
+
+	// Group nodes carry exactly one of the following instead of a type and operator
+	All []ConditionInput `json:"all,omitempty"` // Every nested condition must pass
+	Any []ConditionInput `json:"any,omitempty"` // At least one nested condition must pass
+	Not *ConditionInput  `json:"not,omitempty"` // The nested condition must fail
+
+	// Expression nodes carry only a compact expression, e.g. "level>=30 && item[2000001]>=10"
+	Expression string `json:"expression,omitempty"`
+
+	// Placeholders name a request parameter that supplies the field in place of a literal
+	ValueParam       string `json:"valueParam,omitempty"`       // Parameter holding the value
+	ReferenceIdParam string `json:"referenceIdParam,omitempty"` // Parameter holding the reference ID
+	StepParam        string `json:"stepParam,omitempty"`        // Parameter holding the step
+}
+
+// IsGroup returns whether the input describes a group of nested conditions
+func (i ConditionInput) IsGroup() bool {
+	return i.All != nil || i.Any != nil || i.Not != nil
+}
+
+// groupMembers returns the group type and nested inputs of a group node
+func (i ConditionInput) groupMembers() (GroupType, []ConditionInput, error) {
+	var group GroupType
+	var members []ConditionInput
+	count := 0
+	if i.All != nil {
+		group, members = AllGroup, i.All
+		count++
+	}
+	if i.Any != nil {
+		group, members = AnyGroup, i.Any
+		count++
+	}
+	if i.Not != nil {
+		group, members = NotGroup, []ConditionInput{*i.Not}
+		count++
+	}
+
+	if count == 0 {
+		return "", nil, fmt.Errorf("condition is not a group")
+	}
+	if count > 1 {
+		return "", nil, fmt.Errorf("only one of all, any or not may be specified per group")
+	}
+	if i.Type != "" || i.Operator != "" {
+		return "", nil, fmt.Errorf("group conditions cannot specify a type or operator")
+	}
+	if len(members) == 0 {
+		return "", nil, fmt.Errorf("%s group requires at least one condition", group)
+	}
+	return group, members, nil
 }
 
 // ConditionResult represents the result of a condition evaluation
 type ConditionResult struct {
-	Passed      bool
-	Description string
-	Type        ConditionType
-	Operator    Operator
-	Value       int
-	ItemId      uint32
-	ActualValue int
+	Passed      bool              `json:"passed"`
+	Description string            `json:"description"`
+	Type        ConditionType     `json:"type,omitempty"`
+	Operator    Operator          `json:"operator,omitempty"`
+	Value       int               `json:"value"`
+	ItemId      uint32            `json:"itemId,omitempty"`
+	Values      []int             `json:"values,omitempty"` // Expected set for the in and notIn operators
+	Min         *int              `json:"min,omitempty"`    // Expected range for the between operator
+	Max         *int              `json:"max,omitempty"`
+	ActualValue int               `json:"actualValue"`
+	Group       GroupType         `json:"group,omitempty"`       // Set for group results; ActualValue holds the number of members that passed
+	Children    []ConditionResult `json:"children,omitempty"`    // Results of the group members, in request order
+	Explanation *Explanation      `json:"explanation,omitempty"` // Provenance of the actual value, included in explain mode
+	Breakdown   *StatBreakdown    `json:"breakdown,omitempty"`   // Base and equipment parts of total statistic values
+	ServerTime  *time.Time        `json:"serverTime,omitempty"`  // Server time, in the tenant timezone, time conditions were evaluated at
 }
 
 // Condition represents a validation condition
@@ -73,8 +260,14 @@ type Condition struct {
 	conditionType ConditionType
 	operator      Operator
 	value         int
-	referenceId   uint32 // Used for quest validation, item conditions, etc.
-	step          string // Used for quest progress validation
+	values        []int       // Used by the in and notIn operators
+	min           int         // Used by the between operator
+	max           int         // Used by the between operator
+	referenceId   uint32      // Used for quest validation, item conditions, etc.
+	step          string      // Used for quest progress validation
+	group         GroupType   // Set for group conditions, which have no type or operator
+	children      []Condition // Members of a group condition
+	weight        int         // Score contributed when passing in score mode; 0 means the default of 1
 }
 
 // ConditionBuilder is used to safely construct Condition objects
@@ -82,8 +275,15 @@ type ConditionBuilder struct {
 	conditionType ConditionType
 	operator      Operator
 	value         int
+	values        []int
+	min           *int
+	max           *int
 	referenceId   *uint32
 	step          string
+	group         GroupType
+	children      []Condition
+	weight        *int
+	parameters    Parameters
 	err           error
 }
 
@@ -98,12 +298,11 @@ func (b *ConditionBuilder) SetType(condType string) *ConditionBuilder {
 		return b
 	}
 
-	switch ConditionType(condType) {
-	case JobCondition, MesoCondition, MapCondition, FameCondition, ItemCondition, GenderCondition, LevelCondition, RebornsCondition, DojoPointsCondition, VanquisherKillsCondition, GmLevelCondition, GuildIdCondition, GuildRankCondition, QuestStatusCondition, QuestProgressCondition, UnclaimedMarriageGiftsCondition, StrengthCondition, DexterityCondition, IntelligenceCondition, LuckCondition, GuildLeaderCondition:
-		b.conditionType = ConditionType(condType)
-	default:
-		b.err = fmt.Errorf("unsupported condition type: %s", condType)
+	if _, err := LookupConditionType(condType); err != nil {
+		b.err = err
+		return b
 	}
+	b.conditionType = ConditionType(condType)
 	return b
 }
 
@@ -113,12 +312,7 @@ func (b *ConditionBuilder) SetOperator(op string) *ConditionBuilder {
 		return b
 	}
 
-	switch Operator(op) {
-	case Equals, GreaterThan, LessThan, GreaterEqual, LessEqual:
-		b.operator = Operator(op)
-	default:
-		b.err = fmt.Errorf("unsupported operator: %s", op)
-	}
+	b.operator, b.err = ParseOperator(op)
 	return b
 }
 
@@ -132,6 +326,27 @@ func (b *ConditionBuilder) SetValue(value int) *ConditionBuilder {
 	return b
 }
 
+// SetValues sets the candidate values for the in and notIn operators
+func (b *ConditionBuilder) SetValues(values ...int) *ConditionBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	b.values = values
+	return b
+}
+
+// SetRange sets the inclusive bounds for the between operator
+func (b *ConditionBuilder) SetRange(min int, max int) *ConditionBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	b.min = &min
+	b.max = &max
+	return b
+}
+
 // SetReferenceId sets the reference ID (for quest validation, item conditions, etc.)
 func (b *ConditionBuilder) SetReferenceId(referenceId uint32) *ConditionBuilder {
 	if b.err != nil {
@@ -162,12 +377,87 @@ func (b *ConditionBuilder) SetItemId(itemId uint32) *ConditionBuilder {
 	return b
 }
 
+// SetGroup makes the condition a group combining the provided members
+func (b *ConditionBuilder) SetGroup(group GroupType, children ...Condition) *ConditionBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	switch group {
+	case AllGroup, AnyGroup, NotGroup:
+		b.group = group
+		b.children = children
+	default:
+		b.err = fmt.Errorf("unsupported condition group: %s", group)
+	}
+	return b
+}
+
+// SetWeight sets the score the condition contributes when passing in score mode
+func (b *ConditionBuilder) SetWeight(weight int) *ConditionBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	b.weight = &weight
+	return b
+}
+
+// SetParameters sets the parameters bound to placeholders by FromInput. It must be called before FromInput.
+func (b *ConditionBuilder) SetParameters(parameters Parameters) *ConditionBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	b.parameters = parameters
+	return b
+}
+
 // FromInput creates a condition builder from a ConditionInput
 func (b *ConditionBuilder) FromInput(input ConditionInput) *ConditionBuilder {
+	if input.Expression != "" {
+		parsed, err := input.resolveExpression()
+		if err != nil {
+			b.err = err
+			return b
+		}
+		return b.FromInput(parsed)
+	}
+
+	if input.Weight != nil {
+		b.SetWeight(*input.Weight)
+	}
+
+	if input.IsGroup() {
+		return b.fromGroupInput(input)
+	}
+
+	// Replace placeholders with the values of their parameters
+	if input.hasPlaceholders() {
+		bound, err := input.bind(b.parameters)
+		if err != nil {
+			b.err = err
+			return b
+		}
+		input = bound
+	}
+
 	b.SetType(input.Type)
 	b.SetOperator(input.Operator)
 	b.SetValue(input.Value)
 
+	// Set the operands of the set and range operators
+	if input.Values != nil {
+		b.SetValues(input.Values...)
+	}
+	if input.Min != nil || input.Max != nil {
+		if input.Min == nil || input.Max == nil {
+			b.err = fmt.Errorf("min and max are required for between conditions")
+			return b
+		}
+		b.SetRange(*input.Min, *input.Max)
+	}
+
 	// Handle ReferenceId (preferred) or ItemId (deprecated)
 	if input.ReferenceId != 0 {
 		b.SetReferenceId(input.ReferenceId)
@@ -180,26 +470,36 @@ func (b *ConditionBuilder) FromInput(input ConditionInput) *ConditionBuilder {
 		b.SetStep(input.Step)
 	}
 
-	// Validate required fields for specific condition types
-	switch ConditionType(input.Type) {
-	case ItemCondition:
-		if input.ReferenceId == 0 && input.ItemId == 0 {
-			b.err = fmt.Errorf("referenceId is required for item conditions")
-		}
-	case QuestStatusCondition:
-		if input.ReferenceId == 0 {
-			b.err = fmt.Errorf("referenceId is required for quest conditions")
-		}
-	case QuestProgressCondition:
-		if input.ReferenceId == 0 {
-			b.err = fmt.Errorf("referenceId is required for quest conditions")
-		}
-		if input.Step == "" {
-			b.err = fmt.Errorf("step is required for quest progress conditions")
-		}
+	return b
+}
+
+// FromExpression creates a condition builder from a compact condition expression
+func (b *ConditionBuilder) FromExpression(expression string) *ConditionBuilder {
+	return b.FromInput(ConditionInput{Expression: expression})
+}
+
+// fromGroupInput builds each nested input of a group node and sets them as the group members
+func (b *ConditionBuilder) fromGroupInput(input ConditionInput) *ConditionBuilder {
+	if b.err != nil {
+		return b
 	}
 
-	return b
+	group, members, err := input.groupMembers()
+	if err != nil {
+		b.err = err
+		return b
+	}
+
+	children := make([]Condition, 0, len(members))
+	for i, member := range members {
+		child, err := NewConditionBuilder().SetParameters(b.parameters).FromInput(member).Build()
+		if err != nil {
+			b.err = fmt.Errorf("%s[%d]: %w", group, i, err)
+			return b
+		}
+		children = append(children, child)
+	}
+	return b.SetGroup(group, children...)
 }
 
 // Validate validates the builder state
@@ -208,6 +508,22 @@ func (b *ConditionBuilder) Validate() *ConditionBuilder {
 		return b
 	}
 
+	// Weights must be positive
+	if b.weight != nil && *b.weight < 1 {
+		b.err = fmt.Errorf("weight must be positive")
+		return b
+	}
+
+	// Groups only need a valid set of members
+	if b.group != "" {
+		if len(b.children) == 0 {
+			b.err = fmt.Errorf("%s group requires at least one condition", b.group)
+		} else if b.group == NotGroup && len(b.children) != 1 {
+			b.err = fmt.Errorf("not group requires exactly one condition")
+		}
+		return b
+	}
+
 	// Check if condition type is set
 	if b.conditionType == "" {
 		b.err = fmt.Errorf("condition type is required")
@@ -220,27 +536,36 @@ func (b *ConditionBuilder) Validate() *ConditionBuilder {
 		return b
 	}
 
-	// Check if referenceId is set for conditions that require it
-	switch b.conditionType {
-	case ItemCondition:
-		if b.referenceId == nil {
-			b.err = fmt.Errorf("referenceId is required for item conditions")
-			return b
-		}
-	case QuestStatusCondition:
-		if b.referenceId == nil {
-			b.err = fmt.Errorf("referenceId is required for quest conditions")
-			return b
-		}
-	case QuestProgressCondition:
-		if b.referenceId == nil {
-			b.err = fmt.Errorf("referenceId is required for quest conditions")
-			return b
-		}
-		if b.step == "" {
-			b.err = fmt.Errorf("step is required for quest progress conditions")
-			return b
-		}
+	definition, err := LookupConditionType(string(b.conditionType))
+	if err != nil {
+		b.err = err
+		return b
+	}
+
+	// Check that the operands match the operator
+	if err := validateOperands(definition, b.operator, b.values, b.min, b.max, b.step != ""); err != nil {
+		b.err = err
+		return b
+	}
+
+	// Check the operator, expected values and required fields against the condition type definition
+	expected := []int{b.value}
+	if b.operator.IsSet() {
+		expected = b.values
+	} else if b.operator == Between {
+		expected = []int{*b.min, *b.max}
+	}
+	if err := definition.validate(b.operator, expected, b.referenceId != nil, b.step != ""); err != nil {
+		b.err = err
+		return b
+	}
+	var referenceId uint32
+	if b.referenceId != nil {
+		referenceId = *b.referenceId
+	}
+	if err := definition.validateQualifiers(referenceId, b.step); err != nil {
+		b.err = err
+		return b
 	}
 
 	return b
@@ -259,294 +584,233 @@ func (b *ConditionBuilder) Build() (Condition, error) {
 		operator:      b.operator,
 		value:         b.value,
 		step:          b.step,
+		values:        b.values,
+		group:         b.group,
+		children:      b.children,
+	}
+
+	if b.min != nil && b.max != nil {
+		condition.min = *b.min
+		condition.max = *b.max
 	}
 
 	if b.referenceId != nil {
 		condition.referenceId = *b.referenceId
 	}
 
+	if b.weight != nil {
+		condition.weight = *b.weight
+	}
+
 	return condition, nil
 }
 
 // Evaluate evaluates the condition against a character model
-// Returns a structured ConditionResult with evaluation details
+// Returns a structured ConditionResult with evaluation details. Conditions reading quest or marriage data fail, as
+// only a ValidationContext supplies them.
 func (c Condition) Evaluate(character character.Model) ConditionResult {
-	var actualValue int
-	var passed bool
-	var description string
-	var itemId uint32
-
-	// Get the actual value from the character model based on condition type
-	switch c.conditionType {
-	case JobCondition:
-		actualValue = int(character.JobId())
-		description = fmt.Sprintf("Job ID %s %d", c.operator, c.value)
-	case MesoCondition:
-		actualValue = int(character.Meso())
-		description = fmt.Sprintf("Meso %s %d", c.operator, c.value)
-	case MapCondition:
-		actualValue = int(character.MapId())
-		description = fmt.Sprintf("Map ID %s %d", c.operator, c.value)
-	case FameCondition:
-		actualValue = int(character.Fame())
-		description = fmt.Sprintf("Fame %s %d", c.operator, c.value)
-	case GenderCondition:
-		actualValue = int(character.Gender())
-		description = fmt.Sprintf("Gender %s %d", c.operator, c.value)
-	case LevelCondition:
-		actualValue = int(character.Level())
-		description = fmt.Sprintf("Level %s %d", c.operator, c.value)
-	case RebornsCondition:
-		actualValue = int(character.Reborns())
-		description = fmt.Sprintf("Reborns %s %d", c.operator, c.value)
-	case DojoPointsCondition:
-		actualValue = int(character.DojoPoints())
-		description = fmt.Sprintf("Dojo Points %s %d", c.operator, c.value)
-	case VanquisherKillsCondition:
-		actualValue = int(character.VanquisherKills())
-		description = fmt.Sprintf("Vanquisher Kills %s %d", c.operator, c.value)
-	case GmLevelCondition:
-		actualValue = character.GmLevel()
-		description = fmt.Sprintf("GM Level %s %d", c.operator, c.value)
-	case GuildIdCondition:
-		actualValue = int(character.Guild().Id())
-		if actualValue == 0 {
-			description = fmt.Sprintf("Guild ID %s %d (character not in guild)", c.operator, c.value)
-		} else {
-			description = fmt.Sprintf("Guild ID %s %d", c.operator, c.value)
-		}
-	case GuildLeaderCondition:
-		// For guild leader conditions, we need to check if the character is a guild leader
-		// Get the guild from the character model
-		guild := character.Guild()
-
-		// Check if the character is the guild leader
-		if guild.Id() == 0 {
-			// Character has no guild
-			actualValue = 0
-		} else if guild.LeaderId() == character.Id() {
-			// Character is the guild leader
-			actualValue = 1
-		} else {
-			// Character is not the guild leader
-			actualValue = 0
-		}
+	return c.EvaluateWithContext(characterContext(character))
+}
 
-		description = fmt.Sprintf("Guild Leader %s %d", c.operator, c.value)
-	case GuildRankCondition:
-		actualValue = character.Guild().MemberRank(character.Id())
-		if character.Guild().Id() == 0 {
-			description = fmt.Sprintf("Guild Rank %s %d (character not in guild)", c.operator, c.value)
-		} else {
-			description = fmt.Sprintf("Guild Rank %s %d", c.operator, c.value)
-		}
-	case QuestStatusCondition:
-		// Quest status validation requires context - return error state
-		return ConditionResult{
-			Passed:      false,
-			Description: fmt.Sprintf("Quest %d Status validation requires ValidationContext", c.referenceId),
-			Type:        c.conditionType,
-			Operator:    c.operator,
-			Value:       c.value,
-			ActualValue: int(quest.UNDEFINED),
-		}
-	case QuestProgressCondition:
-		// Quest progress validation requires context - return error state
-		return ConditionResult{
-			Passed:      false,
-			Description: fmt.Sprintf("Quest %d Progress validation (step: %s) requires ValidationContext", c.referenceId, c.step),
-			Type:        c.conditionType,
-			Operator:    c.operator,
-			Value:       c.value,
-			ActualValue: 0,
-		}
-	case UnclaimedMarriageGiftsCondition:
-		// Marriage gifts validation requires context - return error state
-		return ConditionResult{
-			Passed:      false,
-			Description: fmt.Sprintf("Unclaimed Marriage Gifts validation requires ValidationContext"),
-			Type:        c.conditionType,
-			Operator:    c.operator,
-			Value:       c.value,
-			ActualValue: 0,
-		}
-	case StrengthCondition:
-		actualValue = int(character.Strength())
-		description = fmt.Sprintf("Strength %s %d", c.operator, c.value)
-	case DexterityCondition:
-		actualValue = int(character.Dexterity())
-		description = fmt.Sprintf("Dexterity %s %d", c.operator, c.value)
-	case IntelligenceCondition:
-		actualValue = int(character.Intelligence())
-		description = fmt.Sprintf("Intelligence %s %d", c.operator, c.value)
-	case LuckCondition:
-		actualValue = int(character.Luck())
-		description = fmt.Sprintf("Luck %s %d", c.operator, c.value)
-	case ItemCondition:
-		// For item conditions, we need to check the inventory
-		itemQuantity := 0
-		it, ok := inventory2.TypeFromItemId(item.Id(c.referenceId))
-		if !ok {
-			return ConditionResult{
-				Passed:      false,
-				Description: fmt.Sprintf("Invalid item ID: %d", c.referenceId),
-				Type:        c.conditionType,
-				Operator:    c.operator,
-				Value:       c.value,
-				ItemId:      c.referenceId,
-				ActualValue: 0,
-			}
-		}
+// EvaluateWithContext evaluates the condition using a validation context
+// The actual value of each non-group condition is determined by the evaluator registered for its type.
+func (c Condition) EvaluateWithContext(ctx ValidationContext) ConditionResult {
+	if c.group != "" {
+		return c.evaluateGroup(func(child Condition) ConditionResult {
+			return child.EvaluateWithContext(ctx)
+		})
+	}
 
-		compartment := character.Inventory().CompartmentByType(it)
-		for _, a := range compartment.Assets() {
-			if a.TemplateId() == c.referenceId {
-				itemQuantity += int(a.Quantity())
-			}
-		}
+	definition, err := LookupConditionType(string(c.conditionType))
+	if err != nil {
+		return c.newResult(false, fmt.Sprintf("Unsupported condition type: %s", c.conditionType), 0)
+	}
 
-		actualValue = itemQuantity
-		itemId = c.referenceId
-		description = fmt.Sprintf("Item %d quantity %s %d", c.referenceId, c.operator, c.value)
-	default:
-		return ConditionResult{
-			Passed:      false,
-			Description: fmt.Sprintf("Unsupported condition type: %s", c.conditionType),
-			Type:        c.conditionType,
-			Operator:    c.operator,
-			Value:       c.value,
-			ActualValue: 0,
-		}
+	// A condition reading a source whose fetch failed cannot be evaluated
+	if source, ok := ctx.unavailableSource(definition.sourcesFor(c.step)); ok {
+		return c.newResult(false, fmt.Sprintf("%s %s (%s data unavailable)", definition.Name(), c.Expectation(), source), 0)
+	}
+
+	evaluation := definition.Evaluator().Evaluate(c, ctx)
+	if evaluation.Values != nil {
+		c.values = evaluation.Values
 	}
+	passed := !evaluation.Unavailable && c.compare(evaluation.ActualValue)
 
-	// Compare the actual value with the expected value based on the operator
+	result := c.newResult(passed, evaluation.Description, evaluation.ActualValue)
+	result.ItemId = evaluation.ItemId
+	result.Breakdown = evaluation.Breakdown
+	result.ServerTime = evaluation.ServerTime
+	return result
+}
+
+// compare applies the condition operator to the actual value
+func (c Condition) compare(actualValue int) bool {
 	switch c.operator {
 	case Equals:
-		passed = actualValue == c.value
+		return actualValue == c.value
+	case NotEquals:
+		return actualValue != c.value
 	case GreaterThan:
-		passed = actualValue > c.value
+		return actualValue > c.value
 	case LessThan:
-		passed = actualValue < c.value
+		return actualValue < c.value
 	case GreaterEqual:
-		passed = actualValue >= c.value
+		return actualValue >= c.value
 	case LessEqual:
-		passed = actualValue <= c.value
+		return actualValue <= c.value
+	case In:
+		return slices.Contains(c.values, actualValue)
+	case NotIn:
+		return !slices.Contains(c.values, actualValue)
+	case Between:
+		if c.min > c.max {
+			// A range wrapping past the largest value, e.g. the hours 22 through 2
+			return actualValue >= c.min || actualValue <= c.max
+		}
+		return actualValue >= c.min && actualValue <= c.max
+	}
+	return false
+}
+
+// Expectation describes the operator and its operands, e.g. ">= 10", "in [100, 110]" or "between 30 and 50"
+func (c Condition) Expectation() string {
+	switch {
+	case c.operator.IsSet():
+		values := make([]string, 0, len(c.values))
+		for _, v := range c.values {
+			values = append(values, strconv.Itoa(v))
+		}
+		return fmt.Sprintf("%s [%s]", c.operator, strings.Join(values, ", "))
+	case c.operator == Between:
+		return fmt.Sprintf("%s %d and %d", c.operator, c.min, c.max)
+	default:
+		return fmt.Sprintf("%s %d", c.operator, c.value)
 	}
+}
 
-	return ConditionResult{
+// newResult creates a condition result echoing the expected value, set or range of the condition
+func (c Condition) newResult(passed bool, description string, actualValue int) ConditionResult {
+	result := ConditionResult{
 		Passed:      passed,
 		Description: description,
 		Type:        c.conditionType,
 		Operator:    c.operator,
 		Value:       c.value,
-		ItemId:      itemId,
 		ActualValue: actualValue,
 	}
+	if c.operator.IsSet() {
+		result.Values = c.values
+	}
+	if c.operator == Between {
+		min, max := c.min, c.max
+		result.Min = &min
+		result.Max = &max
+	}
+	return result
 }
 
-// EvaluateWithContext evaluates the condition using a validation context
-// This method supports additional validation types like quest status, marriage gifts, etc.
-func (c Condition) EvaluateWithContext(ctx ValidationContext) ConditionResult {
-	var actualValue int
-	var passed bool
-	var description string
-	var itemId uint32
-
-	character := ctx.Character()
-
-	// Handle context-specific conditions first
-	switch c.conditionType {
-	case QuestStatusCondition:
-		questModel, exists := ctx.Quest(c.referenceId)
-		if !exists {
-			return ConditionResult{
-				Passed:      false,
-				Description: fmt.Sprintf("Quest %d not found", c.referenceId),
-				Type:        c.conditionType,
-				Operator:    c.operator,
-				Value:       c.value,
-				ActualValue: int(quest.UNDEFINED),
-			}
-		}
-		actualValue = int(questModel.Status())
-		description = fmt.Sprintf("Quest %d Status %s %d", c.referenceId, c.operator, c.value)
-
-	case QuestProgressCondition:
-		questModel, exists := ctx.Quest(c.referenceId)
-		if !exists {
-			return ConditionResult{
-				Passed:      false,
-				Description: fmt.Sprintf("Quest %d not found", c.referenceId),
-				Type:        c.conditionType,
-				Operator:    c.operator,
-				Value:       c.value,
-				ActualValue: 0,
-			}
-		}
-		actualValue = questModel.Progress(c.step)
-		description = fmt.Sprintf("Quest %d Progress (step: %s) %s %d", c.referenceId, c.step, c.operator, c.value)
-
-	case UnclaimedMarriageGiftsCondition:
-		marriageModel := ctx.Marriage()
-		if marriageModel.HasUnclaimedGifts() {
-			actualValue = 1
-		} else {
-			actualValue = 0
-		}
-		description = fmt.Sprintf("Unclaimed Marriage Gifts %s %d", c.operator, c.value)
-
-	case GuildIdCondition:
-		actualValue = int(character.Guild().Id())
-		if actualValue == 0 {
-			description = fmt.Sprintf("Guild ID %s %d (character not in guild)", c.operator, c.value)
-		} else {
-			description = fmt.Sprintf("Guild ID %s %d", c.operator, c.value)
+// evaluateGroup evaluates every member of a group condition and combines their results
+// All members are evaluated, even once the outcome is known, so the result tree is complete
+func (c Condition) evaluateGroup(evaluate func(Condition) ConditionResult) ConditionResult {
+	children := make([]ConditionResult, 0, len(c.children))
+	passedCount := 0
+	for _, child := range c.children {
+		childResult := evaluate(child)
+		if childResult.Passed {
+			passedCount++
 		}
-
-	case GuildRankCondition:
-		actualValue = character.Guild().MemberRank(character.Id())
-		if character.Guild().Id() == 0 {
-			description = fmt.Sprintf("Guild Rank %s %d (character not in guild)", c.operator, c.value)
-		} else {
-			description = fmt.Sprintf("Guild Rank %s %d", c.operator, c.value)
-		}
-
-	default:
-		// For non-context-specific conditions, delegate to the original Evaluate method
-		return c.Evaluate(character)
+		children = append(children, childResult)
 	}
 
-	// Compare the actual value with the expected value based on the operator
-	switch c.operator {
-	case Equals:
-		passed = actualValue == c.value
-	case GreaterThan:
-		passed = actualValue > c.value
-	case LessThan:
-		passed = actualValue < c.value
-	case GreaterEqual:
-		passed = actualValue >= c.value
-	case LessEqual:
-		passed = actualValue <= c.value
+	var passed bool
+	var description string
+	switch c.group {
+	case AllGroup:
+		passed = passedCount == len(children)
+		description = fmt.Sprintf("All of %d conditions (%d passed)", len(children), passedCount)
+	case AnyGroup:
+		passed = passedCount > 0
+		description = fmt.Sprintf("Any of %d conditions (%d passed)", len(children), passedCount)
+	case NotGroup:
+		passed = passedCount == 0
+		description = fmt.Sprintf("Not (%s)", children[0].Description)
 	}
 
 	return ConditionResult{
 		Passed:      passed,
 		Description: description,
-		Type:        c.conditionType,
-		Operator:    c.operator,
-		Value:       c.value,
-		ItemId:      itemId,
-		ActualValue: actualValue,
+		Group:       c.group,
+		ActualValue: passedCount,
+		Children:    children,
 	}
 }
 
+// Weight returns the score the condition contributes when passing in score mode
+func (c Condition) Weight() int {
+	if c.weight == 0 {
+		return 1
+	}
+	return c.weight
+}
+
+// ReferenceId returns the quest, item or other entity the condition refers to, or 0 when it has none
+func (c Condition) ReferenceId() uint32 {
+	return c.referenceId
+}
+
+// Step returns the step qualifying the condition, or an empty string when it has none
+func (c Condition) Step() string {
+	return c.step
+}
+
+// Operator returns the comparison operator of the condition
+func (c Condition) Operator() Operator {
+	return c.operator
+}
+
+// Value returns the value compared against by single value operators
+func (c Condition) Value() int {
+	return c.value
+}
+
+// Values returns the values compared against by the in and notIn operators
+func (c Condition) Values() []int {
+	return slices.Clone(c.values)
+}
+
+// Min returns the lower bound of the between operator
+func (c Condition) Min() int {
+	return c.min
+}
+
+// Max returns the upper bound of the between operator
+func (c Condition) Max() int {
+	return c.max
+}
+
+// leaves returns the non-group conditions contained in the condition tree
+func (c Condition) leaves() []Condition {
+	if c.group == "" {
+		return []Condition{c}
+	}
+	var result []Condition
+	for _, child := range c.children {
+		result = append(result, child.leaves()...)
+	}
+	return result
+}
+
 // ValidationResult represents the result of a validation
 type ValidationResult struct {
 	passed      bool
 	details     []string
 	results     []ConditionResult
 	characterId uint32
+	explain     bool
+	mode        Mode
+	passedCount int // Number of top-level conditions that passed
+	score       int // Summed weight of the top-level conditions that passed
 }
 
 // NewValidationResult creates a new validation result
@@ -556,6 +820,7 @@ func NewValidationResult(characterId uint32) ValidationResult {
 		details:     []string{},
 		results:     []ConditionResult{},
 		characterId: characterId,
+		mode:        Mode{modeType: AllMode},
 	}
 }
 
@@ -574,15 +839,49 @@ func (v ValidationResult) Results() []ConditionResult {
 	return v.results
 }
 
+// Explained returns whether the result was decorated with Explain
+func (v ValidationResult) Explained() bool {
+	return v.explain
+}
+
+// Mode returns the mode used to decide whether the validation passed
+func (v ValidationResult) Mode() Mode {
+	return v.mode
+}
+
+// PassedCount returns the number of top-level conditions that passed
+func (v ValidationResult) PassedCount() int {
+	return v.passedCount
+}
+
+// TotalCount returns the number of top-level conditions evaluated
+func (v ValidationResult) TotalCount() int {
+	return len(v.results)
+}
+
+// Score returns the summed weight of the top-level conditions that passed
+func (v ValidationResult) Score() int {
+	return v.score
+}
+
 // CharacterId returns the character ID that was validated
 func (v ValidationResult) CharacterId() uint32 {
 	return v.characterId
 }
 
-// AddConditionResult adds a structured condition result to the validation result
+// AddConditionResult adds a structured condition result with the default weight to the validation result
 func (v *ValidationResult) AddConditionResult(result ConditionResult) {
+	v.AddWeightedConditionResult(result, 1)
+}
+
+// AddWeightedConditionResult adds a structured condition result to the validation result, contributing its weight to
+// the score when it passes
+func (v *ValidationResult) AddWeightedConditionResult(result ConditionResult, weight int) {
 	if !result.Passed {
 		v.passed = false
+	} else {
+		v.passedCount++
+		v.score += weight
 	}
 	status := "Passed"
 	if !result.Passed {
@@ -17,7 +17,6 @@ import (
 	"time"
 )
 
-
 func TestCondition_Evaluate(t *testing.T) {
 	// Create test inventory with items
 	compartmentId := uuid.New()
@@ -60,7 +59,7 @@ func TestCondition_Evaluate(t *testing.T) {
 				conditionType: ItemCondition,
 				operator:      Equals,
 				value:         10,
-				referenceId:        2000001,
+				referenceId:   2000001,
 			},
 			wantPassed:   true,
 			wantContains: "Item 2000001 quantity = 10",
@@ -71,7 +70,7 @@ func TestCondition_Evaluate(t *testing.T) {
 				conditionType: ItemCondition,
 				operator:      Equals,
 				value:         15,
-				referenceId:        2000001,
+				referenceId:   2000001,
 			},
 			wantPassed:   false,
 			wantContains: "Item 2000001 quantity = 15",
@@ -82,7 +81,7 @@ func TestCondition_Evaluate(t *testing.T) {
 				conditionType: ItemCondition,
 				operator:      GreaterThan,
 				value:         5,
-				referenceId:        2000001,
+				referenceId:   2000001,
 			},
 			wantPassed:   true,
 			wantContains: "Item 2000001 quantity > 5",
@@ -93,7 +92,7 @@ func TestCondition_Evaluate(t *testing.T) {
 				conditionType: ItemCondition,
 				operator:      GreaterThan,
 				value:         15,
-				referenceId:        2000001,
+				referenceId:   2000001,
 			},
 			wantPassed:   false,
 			wantContains: "Item 2000001 quantity > 15",
@@ -104,7 +103,7 @@ func TestCondition_Evaluate(t *testing.T) {
 				conditionType: ItemCondition,
 				operator:      LessThan,
 				value:         15,
-				referenceId:        2000001,
+				referenceId:   2000001,
 			},
 			wantPassed:   true,
 			wantContains: "Item 2000001 quantity < 15",
@@ -115,7 +114,7 @@ func TestCondition_Evaluate(t *testing.T) {
 				conditionType: ItemCondition,
 				operator:      LessThan,
 				value:         5,
-				referenceId:        2000001,
+				referenceId:   2000001,
 			},
 			wantPassed:   false,
 			wantContains: "Item 2000001 quantity < 5",
@@ -126,7 +125,7 @@ func TestCondition_Evaluate(t *testing.T) {
 				conditionType: ItemCondition,
 				operator:      GreaterEqual,
 				value:         10,
-				referenceId:        2000001,
+				referenceId:   2000001,
 			},
 			wantPassed:   true,
 			wantContains: "Item 2000001 quantity >= 10",
@@ -137,7 +136,7 @@ func TestCondition_Evaluate(t *testing.T) {
 				conditionType: ItemCondition,
 				operator:      GreaterEqual,
 				value:         5,
-				referenceId:        2000001,
+				referenceId:   2000001,
 			},
 			wantPassed:   true,
 			wantContains: "Item 2000001 quantity >= 5",
@@ -148,7 +147,7 @@ func TestCondition_Evaluate(t *testing.T) {
 				conditionType: ItemCondition,
 				operator:      GreaterEqual,
 				value:         15,
-				referenceId:        2000001,
+				referenceId:   2000001,
 			},
 			wantPassed:   false,
 			wantContains: "Item 2000001 quantity >= 15",
@@ -159,7 +158,7 @@ func TestCondition_Evaluate(t *testing.T) {
 				conditionType: ItemCondition,
 				operator:      LessEqual,
 				value:         10,
-				referenceId:        2000001,
+				referenceId:   2000001,
 			},
 			wantPassed:   true,
 			wantContains: "Item 2000001 quantity <= 10",
@@ -170,7 +169,7 @@ func TestCondition_Evaluate(t *testing.T) {
 				conditionType: ItemCondition,
 				operator:      LessEqual,
 				value:         15,
-				referenceId:        2000001,
+				referenceId:   2000001,
 			},
 			wantPassed:   true,
 			wantContains: "Item 2000001 quantity <= 15",
@@ -181,7 +180,7 @@ func TestCondition_Evaluate(t *testing.T) {
 				conditionType: ItemCondition,
 				operator:      LessEqual,
 				value:         5,
-				referenceId:        2000001,
+				referenceId:   2000001,
 			},
 			wantPassed:   false,
 			wantContains: "Item 2000001 quantity <= 5",
@@ -192,7 +191,7 @@ func TestCondition_Evaluate(t *testing.T) {
 				conditionType: ItemCondition,
 				operator:      Equals,
 				value:         10,
-				referenceId:        9999999, // Non-existent item
+				referenceId:   9999999, // Non-existent item
 			},
 			wantPassed:   false,
 			wantContains: "Invalid item ID: 9999999",
@@ -1015,7 +1014,7 @@ func TestCondition_EvaluateWithContext(t *testing.T) {
 			wantContains: "Quest 1002 Status = 3",
 		},
 		{
-			name: "Quest Status - quest not found",
+			name: "Quest Status - quest not recorded",
 			condition: Condition{
 				conditionType: QuestStatusCondition,
 				operator:      Equals,
@@ -1024,7 +1023,7 @@ func TestCondition_EvaluateWithContext(t *testing.T) {
 			},
 			context:      contextWithData,
 			wantPassed:   false,
-			wantContains: "Quest 9999 not found",
+			wantContains: "Quest 9999 Status = 2",
 		},
 		// Quest Progress condition tests
 		{
@@ -1093,7 +1092,7 @@ func TestCondition_EvaluateWithContext(t *testing.T) {
 			wantContains: "Quest 1001 Progress (step: nonexistent) = 0",
 		},
 		{
-			name: "Quest Progress - quest not found",
+			name: "Quest Progress - quest not recorded",
 			condition: Condition{
 				conditionType: QuestProgressCondition,
 				operator:      Equals,
@@ -1103,7 +1102,7 @@ func TestCondition_EvaluateWithContext(t *testing.T) {
 			},
 			context:      contextWithData,
 			wantPassed:   false,
-			wantContains: "Quest 9999 not found",
+			wantContains: "Quest 9999 Progress (step: step1) = 5",
 		},
 		// Marriage gifts condition tests
 		{
@@ -1427,18 +1426,18 @@ func TestCondition_Evaluate_WithGuild(t *testing.T) {
 
 	// Create a guild for the character
 	guildRestModel := guild.RestModel{
-		Id:       1001,
-		WorldId:  1,
-		Name:     "TestGuild",
-		Notice:   "Test guild notice",
-		Points:   1000,
-		Capacity: 100,
-		Logo:     1,
-		LogoColor: 1,
-		LogoBackground: 1,
+		Id:                  1001,
+		WorldId:             1,
+		Name:                "TestGuild",
+		Notice:              "Test guild notice",
+		Points:              1000,
+		Capacity:            100,
+		Logo:                1,
+		LogoColor:           1,
+		LogoBackground:      1,
 		LogoBackgroundColor: 1,
-		LeaderId: 123,
-		Members:  []member.RestModel{
+		LeaderId:            123,
+		Members: []member.RestModel{
 			{
 				CharacterId:  123,
 				Name:         "TestMember",
@@ -1449,7 +1448,7 @@ func TestCondition_Evaluate_WithGuild(t *testing.T) {
 				AllianceRank: 0,
 			},
 		},
-		Titles:   []title.RestModel{},
+		Titles: []title.RestModel{},
 	}
 	guildModel, _ := guild.Extract(guildRestModel)
 
@@ -1671,9 +1670,9 @@ func TestCondition_ErrorHandling(t *testing.T) {
 // TestConditionBuilder_ErrorHandling tests error scenarios for condition builder
 func TestConditionBuilder_ErrorHandling(t *testing.T) {
 	tests := []struct {
-		name        string
-		input       ConditionInput
-		wantError   bool
+		name          string
+		input         ConditionInput
+		wantError     bool
 		errorContains string
 	}{
 		// Test invalid condition type
@@ -1692,7 +1691,7 @@ func TestConditionBuilder_ErrorHandling(t *testing.T) {
 			name: "Invalid operator",
 			input: ConditionInput{
 				Type:     "level",
-				Operator: "!=",
+				Operator: "=>",
 				Value:    25,
 			},
 			wantError:     true,
@@ -1813,9 +1812,9 @@ func TestConditionWithContext_ErrorHandling(t *testing.T) {
 		wantContains string
 		wantError    bool
 	}{
-		// Test quest not found
+		// Test quest not recorded, which is not started
 		{
-			name: "Quest Status - quest not found",
+			name: "Quest Status - quest not recorded",
 			condition: Condition{
 				conditionType: QuestStatusCondition,
 				operator:      Equals,
@@ -1824,11 +1823,11 @@ func TestConditionWithContext_ErrorHandling(t *testing.T) {
 			},
 			context:      emptyContext,
 			wantPassed:   false,
-			wantContains: "Quest 9999 not found",
+			wantContains: "Quest 9999 Status = 2",
 			wantError:    true,
 		},
 		{
-			name: "Quest Progress - quest not found",
+			name: "Quest Progress - quest not recorded",
 			condition: Condition{
 				conditionType: QuestProgressCondition,
 				operator:      Equals,
@@ -1838,7 +1837,7 @@ func TestConditionWithContext_ErrorHandling(t *testing.T) {
 			},
 			context:      emptyContext,
 			wantPassed:   false,
-			wantContains: "Quest 9999 not found",
+			wantContains: "Quest 9999 Progress (step: step1) = 5",
 			wantError:    true,
 		},
 		// Test zero reference ID scenarios
@@ -1852,7 +1851,7 @@ func TestConditionWithContext_ErrorHandling(t *testing.T) {
 			},
 			context:      emptyContext,
 			wantPassed:   false,
-			wantContains: "Quest 0 not found",
+			wantContains: "Quest 0 Status = 2",
 			wantError:    true,
 		},
 		{
@@ -1880,7 +1879,7 @@ func TestConditionWithContext_ErrorHandling(t *testing.T) {
 			},
 			context:      emptyContext,
 			wantPassed:   false,
-			wantContains: "Quest 1001 not found",
+			wantContains: "Quest 1001 Progress (step: ) = 5",
 			wantError:    true,
 		},
 	}
@@ -1944,3 +1943,333 @@ func TestValidationContext_ErrorHandling(t *testing.T) {
 		}
 	})
 }
+
+// TestCondition_Evaluate_Groups tests evaluation of nested all/any/not condition groups
+func TestCondition_Evaluate_Groups(t *testing.T) {
+	character := character.NewModelBuilder().
+		SetId(123).
+		SetJobId(100).
+		SetLevel(30).
+		SetMeso(5000).
+		Build()
+
+	level := ConditionInput{Type: "level", Operator: ">=", Value: 30}
+	job := ConditionInput{Type: "jobId", Operator: "=", Value: 200}
+	meso := ConditionInput{Type: "meso", Operator: ">=", Value: 10000}
+
+	tests := []struct {
+		name             string
+		input            ConditionInput
+		wantPassed       bool
+		wantContains     string
+		wantChildPassed  []bool
+		wantPassedMember int
+	}{
+		{
+			name:             "All group - pass",
+			input:            ConditionInput{All: []ConditionInput{level, {Type: "jobId", Operator: "=", Value: 100}}},
+			wantPassed:       true,
+			wantContains:     "All of 2 conditions (2 passed)",
+			wantChildPassed:  []bool{true, true},
+			wantPassedMember: 2,
+		},
+		{
+			name:             "All group - fail",
+			input:            ConditionInput{All: []ConditionInput{level, job}},
+			wantPassed:       false,
+			wantContains:     "All of 2 conditions (1 passed)",
+			wantChildPassed:  []bool{true, false},
+			wantPassedMember: 1,
+		},
+		{
+			name:             "Any group - pass",
+			input:            ConditionInput{Any: []ConditionInput{job, level}},
+			wantPassed:       true,
+			wantContains:     "Any of 2 conditions (1 passed)",
+			wantChildPassed:  []bool{false, true},
+			wantPassedMember: 1,
+		},
+		{
+			name:             "Any group - fail",
+			input:            ConditionInput{Any: []ConditionInput{job, meso}},
+			wantPassed:       false,
+			wantContains:     "Any of 2 conditions (0 passed)",
+			wantChildPassed:  []bool{false, false},
+			wantPassedMember: 0,
+		},
+		{
+			name:             "Not group - pass",
+			input:            ConditionInput{Not: &job},
+			wantPassed:       true,
+			wantContains:     "Not (Job ID = 200)",
+			wantChildPassed:  []bool{false},
+			wantPassedMember: 0,
+		},
+		{
+			name:             "Not group - fail",
+			input:            ConditionInput{Not: &level},
+			wantPassed:       false,
+			wantContains:     "Not (Level >= 30)",
+			wantChildPassed:  []bool{true},
+			wantPassedMember: 1,
+		},
+		{
+			name: "Nested groups - pass",
+			input: ConditionInput{All: []ConditionInput{
+				level,
+				{Any: []ConditionInput{job, {Not: &meso}}},
+			}},
+			wantPassed:       true,
+			wantContains:     "All of 2 conditions (2 passed)",
+			wantChildPassed:  []bool{true, true},
+			wantPassedMember: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, err := NewConditionBuilder().FromInput(tt.input).Build()
+			if err != nil {
+				t.Fatalf("Unexpected error building condition: %v", err)
+			}
+
+			for _, result := range []ConditionResult{
+				condition.Evaluate(character),
+				condition.EvaluateWithContext(NewValidationContext(character)),
+			} {
+				if result.Passed != tt.wantPassed {
+					t.Errorf("Passed = %v, want %v", result.Passed, tt.wantPassed)
+				}
+				if !strings.Contains(result.Description, tt.wantContains) {
+					t.Errorf("Description = %v, want to contain %v", result.Description, tt.wantContains)
+				}
+				if result.ActualValue != tt.wantPassedMember {
+					t.Errorf("ActualValue = %v, want %v", result.ActualValue, tt.wantPassedMember)
+				}
+				if len(result.Children) != len(tt.wantChildPassed) {
+					t.Fatalf("Children count = %v, want %v", len(result.Children), len(tt.wantChildPassed))
+				}
+				for i, want := range tt.wantChildPassed {
+					if result.Children[i].Passed != want {
+						t.Errorf("Children[%d].Passed = %v, want %v", i, result.Children[i].Passed, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestConditionBuilder_GroupErrorHandling tests error scenarios when building condition groups
+func TestConditionBuilder_GroupErrorHandling(t *testing.T) {
+	level := ConditionInput{Type: "level", Operator: ">=", Value: 30}
+
+	tests := []struct {
+		name          string
+		input         ConditionInput
+		errorContains string
+	}{
+		{
+			name:          "Empty all group",
+			input:         ConditionInput{All: []ConditionInput{}},
+			errorContains: "all group requires at least one condition",
+		},
+		{
+			name:          "Multiple connectives",
+			input:         ConditionInput{All: []ConditionInput{level}, Any: []ConditionInput{level}},
+			errorContains: "only one of all, any or not",
+		},
+		{
+			name:          "Group with type",
+			input:         ConditionInput{Type: "level", Any: []ConditionInput{level}},
+			errorContains: "cannot specify a type or operator",
+		},
+		{
+			name:          "Invalid nested member",
+			input:         ConditionInput{All: []ConditionInput{level, {Any: []ConditionInput{{Type: "invalid", Operator: "="}}}}},
+			errorContains: "all[1]: any[0]: unsupported condition type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewConditionBuilder().FromInput(tt.input).Build()
+			if err == nil {
+				t.Fatalf("Expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.errorContains) {
+				t.Errorf("Expected error containing '%s', got '%v'", tt.errorContains, err)
+			}
+			if validateErr := validateConditionInput(tt.input); validateErr == nil {
+				t.Errorf("Expected validateConditionInput error, got nil")
+			}
+		})
+	}
+}
+
+// TestCondition_Evaluate_ExtendedOperators tests the !=, in, notIn and between operators
+func TestCondition_Evaluate_ExtendedOperators(t *testing.T) {
+	character := character.NewModelBuilder().
+		SetId(123).
+		SetJobId(110).
+		SetLevel(45).
+		SetMapId(100000000).
+		Build()
+
+	tests := []struct {
+		name         string
+		input        ConditionInput
+		wantPassed   bool
+		wantContains string
+		wantValues   []int
+		wantMin      int
+		wantMax      int
+	}{
+		{
+			name:         "Not equals - pass",
+			input:        ConditionInput{Type: "mapId", Operator: "!=", Value: 910000000},
+			wantPassed:   true,
+			wantContains: "Map ID != 910000000",
+		},
+		{
+			name:         "Not equals - fail",
+			input:        ConditionInput{Type: "mapId", Operator: "!=", Value: 100000000},
+			wantPassed:   false,
+			wantContains: "Map ID != 100000000",
+		},
+		{
+			name:         "In - pass",
+			input:        ConditionInput{Type: "jobId", Operator: "in", Values: []int{100, 110, 120, 130}},
+			wantPassed:   true,
+			wantContains: "Job ID in [100, 110, 120, 130]",
+			wantValues:   []int{100, 110, 120, 130},
+		},
+		{
+			name:         "In - fail",
+			input:        ConditionInput{Type: "jobId", Operator: "in", Values: []int{200, 210}},
+			wantPassed:   false,
+			wantContains: "Job ID in [200, 210]",
+			wantValues:   []int{200, 210},
+		},
+		{
+			name:         "Not in - pass",
+			input:        ConditionInput{Type: "jobId", Operator: "notIn", Values: []int{200, 210}},
+			wantPassed:   true,
+			wantContains: "Job ID notIn [200, 210]",
+			wantValues:   []int{200, 210},
+		},
+		{
+			name:         "Not in - fail",
+			input:        ConditionInput{Type: "jobId", Operator: "notIn", Values: []int{110}},
+			wantPassed:   false,
+			wantContains: "Job ID notIn [110]",
+			wantValues:   []int{110},
+		},
+		{
+			name:         "Between - pass",
+			input:        ConditionInput{Type: "level", Operator: "between", Min: intPtr(30), Max: intPtr(50)},
+			wantPassed:   true,
+			wantContains: "Level between 30 and 50",
+			wantMin:      30,
+			wantMax:      50,
+		},
+		{
+			name:         "Between - inclusive bound",
+			input:        ConditionInput{Type: "level", Operator: "between", Min: intPtr(45), Max: intPtr(45)},
+			wantPassed:   true,
+			wantContains: "Level between 45 and 45",
+			wantMin:      45,
+			wantMax:      45,
+		},
+		{
+			name:         "Between - fail",
+			input:        ConditionInput{Type: "level", Operator: "between", Min: intPtr(50), Max: intPtr(70)},
+			wantPassed:   false,
+			wantContains: "Level between 50 and 70",
+			wantMin:      50,
+			wantMax:      70,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, err := NewConditionBuilder().FromInput(tt.input).Build()
+			if err != nil {
+				t.Fatalf("Unexpected error building condition: %v", err)
+			}
+
+			result := condition.Evaluate(character)
+			if result.Passed != tt.wantPassed {
+				t.Errorf("Passed = %v, want %v", result.Passed, tt.wantPassed)
+			}
+			if !strings.Contains(result.Description, tt.wantContains) {
+				t.Errorf("Description = %v, want to contain %v", result.Description, tt.wantContains)
+			}
+			if len(result.Values) != len(tt.wantValues) {
+				t.Errorf("Values = %v, want %v", result.Values, tt.wantValues)
+			}
+			if tt.input.Operator == "between" {
+				if result.Min == nil || result.Max == nil || *result.Min != tt.wantMin || *result.Max != tt.wantMax {
+					t.Errorf("Range = [%v, %v], want [%v, %v]", result.Min, result.Max, tt.wantMin, tt.wantMax)
+				}
+			} else if result.Min != nil || result.Max != nil {
+				t.Errorf("Range should not be set for operator %s", tt.input.Operator)
+			}
+		})
+	}
+}
+
+// TestConditionBuilder_OperandErrorHandling tests validation of set and range operands
+func TestConditionBuilder_OperandErrorHandling(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         ConditionInput
+		errorContains string
+	}{
+		{
+			name:          "In without values",
+			input:         ConditionInput{Type: "jobId", Operator: "in"},
+			errorContains: "values are required for in conditions",
+		},
+		{
+			name:          "Values with comparison operator",
+			input:         ConditionInput{Type: "jobId", Operator: "=", Values: []int{100}},
+			errorContains: "values are only supported by the in and notIn operators",
+		},
+		{
+			name:          "Between without max",
+			input:         ConditionInput{Type: "level", Operator: "between", Min: intPtr(10)},
+			errorContains: "min and max are required for between conditions",
+		},
+		{
+			name:          "Between with inverted range",
+			input:         ConditionInput{Type: "level", Operator: "between", Min: intPtr(50), Max: intPtr(10)},
+			errorContains: "min must not be greater than max",
+		},
+		{
+			name:          "Range with comparison operator",
+			input:         ConditionInput{Type: "level", Operator: ">=", Min: intPtr(10), Max: intPtr(20)},
+			errorContains: "min and max are only supported by the between operator",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewConditionBuilder().FromInput(tt.input).Build()
+			if err == nil || !strings.Contains(err.Error(), tt.errorContains) {
+				t.Errorf("Expected error containing '%s', got '%v'", tt.errorContains, err)
+			}
+			if err := validateConditionInput(tt.input); err == nil || !strings.Contains(err.Error(), tt.errorContains) {
+				t.Errorf("Expected validateConditionInput error containing '%s', got '%v'", tt.errorContains, err)
+			}
+		})
+	}
+
+	// Type specific value checks apply to every operand
+	if err := validateConditionInput(ConditionInput{Type: "questStatus", Operator: "in", Values: []int{2, 7}, ReferenceId: 1001}); err == nil {
+		t.Errorf("Expected error for out of range quest status value, got nil")
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
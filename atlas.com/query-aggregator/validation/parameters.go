@@ -0,0 +1,169 @@
+package validation
+
+import (
+	"fmt"
+	"math"
+)
+
+// Parameters supplies the values of condition placeholders at request time, keyed by parameter name.
+// Numbers decoded from JSON arrive as float64 and are accepted when they hold a whole number.
+type Parameters map[string]interface{}
+
+// ParameterError reports a placeholder whose parameter is missing from the request or holds a value of the wrong type
+type ParameterError struct {
+	Parameter string      // Name of the parameter
+	Expected  string      // Type the placeholder requires: integer, string or referenceId
+	Missing   bool        // Set when the request does not supply the parameter
+	Got       interface{} // Value supplied for the parameter
+}
+
+// expectedDescriptions describes each expected parameter type in error messages
+var expectedDescriptions = map[string]string{
+	"integer":     "an integer",
+	"string":      "a string",
+	"referenceId": "a valid reference id",
+}
+
+func (e ParameterError) Error() string {
+	if e.Missing {
+		return fmt.Sprintf("missing parameter: %s", e.Parameter)
+	}
+	return fmt.Sprintf("parameter %s must be %s, got %v", e.Parameter, expectedDescriptions[e.Expected], e.Got)
+}
+
+// integer returns the named parameter as an integer
+func (p Parameters) integer(name string) (int, error) {
+	raw, ok := p[name]
+	if !ok {
+		return 0, ParameterError{Parameter: name, Expected: "integer", Missing: true}
+	}
+
+	switch v := raw.(type) {
+	case int:
+		return v, nil
+	case int32:
+		return int(v), nil
+	case int64:
+		return int(v), nil
+	case uint32:
+		return int(v), nil
+	case float64:
+		if v == math.Trunc(v) && v >= math.MinInt32 && v <= math.MaxUint32 {
+			return int(v), nil
+		}
+	}
+	return 0, ParameterError{Parameter: name, Expected: "integer", Got: raw}
+}
+
+// string returns the named parameter as a string
+func (p Parameters) string(name string) (string, error) {
+	raw, ok := p[name]
+	if !ok {
+		return "", ParameterError{Parameter: name, Expected: "string", Missing: true}
+	}
+
+	v, ok := raw.(string)
+	if !ok {
+		return "", ParameterError{Parameter: name, Expected: "string", Got: raw}
+	}
+	return v, nil
+}
+
+// hasPlaceholders returns whether the input itself, ignoring any nested members, references a parameter
+func (i ConditionInput) hasPlaceholders() bool {
+	return i.ValueParam != "" || i.ReferenceIdParam != "" || i.StepParam != ""
+}
+
+// validatePlaceholders checks that no field is supplied both literally and by a placeholder
+func (i ConditionInput) validatePlaceholders() error {
+	if i.ValueParam != "" && i.Value != 0 {
+		return fmt.Errorf("value and valueParam are mutually exclusive")
+	}
+	if i.ReferenceIdParam != "" && (i.ReferenceId != 0 || i.ItemId != 0) {
+		return fmt.Errorf("referenceId and referenceIdParam are mutually exclusive")
+	}
+	if i.StepParam != "" && i.Step != "" {
+		return fmt.Errorf("step and stepParam are mutually exclusive")
+	}
+	return nil
+}
+
+// bind returns a copy of the input with its placeholders replaced by the values of their parameters
+func (i ConditionInput) bind(parameters Parameters) (ConditionInput, error) {
+	if err := i.validatePlaceholders(); err != nil {
+		return ConditionInput{}, err
+	}
+
+	if i.ValueParam != "" {
+		value, err := parameters.integer(i.ValueParam)
+		if err != nil {
+			return ConditionInput{}, err
+		}
+		i.Value = value
+		i.ValueParam = ""
+	}
+
+	if i.ReferenceIdParam != "" {
+		referenceId, err := parameters.integer(i.ReferenceIdParam)
+		if err != nil {
+			return ConditionInput{}, err
+		}
+		if referenceId < 0 || referenceId > math.MaxUint32 {
+			return ConditionInput{}, ParameterError{Parameter: i.ReferenceIdParam, Expected: "referenceId", Got: referenceId}
+		}
+		i.ReferenceId = uint32(referenceId)
+		i.ReferenceIdParam = ""
+	}
+
+	if i.StepParam != "" {
+		step, err := parameters.string(i.StepParam)
+		if err != nil {
+			return ConditionInput{}, err
+		}
+		i.Step = step
+		i.StepParam = ""
+	}
+
+	return i, nil
+}
+
+// BindParameters resolves every placeholder in a condition input tree, expanding expressions into their structured form
+func BindParameters(parameters Parameters) func(input ConditionInput) (ConditionInput, error) {
+	return func(input ConditionInput) (ConditionInput, error) {
+		if input.Expression != "" {
+			parsed, err := input.resolveExpression()
+			if err != nil {
+				return ConditionInput{}, err
+			}
+			return BindParameters(parameters)(parsed)
+		}
+
+		if !input.IsGroup() {
+			return input.bind(parameters)
+		}
+
+		group, members, err := input.groupMembers()
+		if err != nil {
+			return ConditionInput{}, err
+		}
+		bound := make([]ConditionInput, 0, len(members))
+		for i, member := range members {
+			b, err := BindParameters(parameters)(member)
+			if err != nil {
+				return ConditionInput{}, fmt.Errorf("%s[%d]: %w", group, i, err)
+			}
+			bound = append(bound, b)
+		}
+
+		result := ConditionInput{Weight: input.Weight}
+		switch group {
+		case AllGroup:
+			result.All = bound
+		case AnyGroup:
+			result.Any = bound
+		default:
+			result.Not = &bound[0]
+		}
+		return result, nil
+	}
+}
@@ -0,0 +1,244 @@
+package validation
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestBindParameters tests that placeholders resolve to request parameters
+func TestBindParameters(t *testing.T) {
+	parameters := Parameters{
+		"count":    float64(10),
+		"itemId":   float64(2000001),
+		"questId":  uint32(1001),
+		"step":     "mobsKilled",
+		"minLevel": 30,
+	}
+
+	tests := []struct {
+		name  string
+		input ConditionInput
+		want  ConditionInput
+	}{
+		{
+			name:  "Value and reference placeholders",
+			input: ConditionInput{Type: "item", Operator: ">=", ValueParam: "count", ReferenceIdParam: "itemId"},
+			want:  ConditionInput{Type: "item", Operator: ">=", Value: 10, ReferenceId: 2000001},
+		},
+		{
+			name:  "Step placeholder",
+			input: ConditionInput{Type: "questProgress", Operator: ">=", Value: 5, ReferenceIdParam: "questId", StepParam: "step"},
+			want:  ConditionInput{Type: "questProgress", Operator: ">=", Value: 5, ReferenceId: 1001, Step: "mobsKilled"},
+		},
+		{
+			name:  "Literal condition is unchanged",
+			input: ConditionInput{Type: "level", Operator: ">=", Value: 30},
+			want:  ConditionInput{Type: "level", Operator: ">=", Value: 30},
+		},
+		{
+			name: "Nested group",
+			input: ConditionInput{Any: []ConditionInput{
+				{Type: "level", Operator: ">=", ValueParam: "minLevel"},
+				{Not: &ConditionInput{Type: "item", Operator: ">=", ValueParam: "count", ReferenceIdParam: "itemId"}},
+			}},
+			want: ConditionInput{Any: []ConditionInput{
+				{Type: "level", Operator: ">=", Value: 30},
+				{Not: &ConditionInput{Type: "item", Operator: ">=", Value: 10, ReferenceId: 2000001}},
+			}},
+		},
+		{
+			name:  "Expression placeholders",
+			input: ConditionInput{Expression: "level>=$minLevel && item[$itemId]>=$count"},
+			want: ConditionInput{All: []ConditionInput{
+				{Type: "level", Operator: ">=", Value: 30},
+				{Type: "item", Operator: ">=", Value: 10, ReferenceId: 2000001},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BindParameters(parameters)(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BindParameters() = %+v, want %+v", got, tt.want)
+			}
+
+			// The builder resolves placeholders to the same condition
+			fromTemplate, err := NewConditionBuilder().SetParameters(parameters).FromInput(tt.input).Build()
+			if err != nil {
+				t.Fatalf("Unexpected builder error: %v", err)
+			}
+			fromBound, err := NewConditionBuilder().FromInput(tt.want).Build()
+			if err != nil {
+				t.Fatalf("Unexpected builder error: %v", err)
+			}
+			if !reflect.DeepEqual(fromTemplate, fromBound) {
+				t.Errorf("FromInput() = %+v, want %+v", fromTemplate, fromBound)
+			}
+		})
+	}
+}
+
+// TestBindParameters_Errors tests rejection of missing and mistyped parameters
+func TestBindParameters_Errors(t *testing.T) {
+	parameters := Parameters{
+		"count":    float64(10),
+		"fraction": 2.5,
+		"text":     "ten",
+		"negative": float64(-1),
+	}
+
+	tests := []struct {
+		name          string
+		input         ConditionInput
+		errorContains string
+	}{
+		{
+			name:          "Missing parameter",
+			input:         ConditionInput{Type: "level", Operator: ">=", ValueParam: "minLevel"},
+			errorContains: "missing parameter: minLevel",
+		},
+		{
+			name:          "String for value",
+			input:         ConditionInput{Type: "level", Operator: ">=", ValueParam: "text"},
+			errorContains: "parameter text must be an integer",
+		},
+		{
+			name:          "Fractional value",
+			input:         ConditionInput{Type: "level", Operator: ">=", ValueParam: "fraction"},
+			errorContains: "parameter fraction must be an integer",
+		},
+		{
+			name:          "Negative reference id",
+			input:         ConditionInput{Type: "item", Operator: ">=", Value: 1, ReferenceIdParam: "negative"},
+			errorContains: "parameter negative must be a valid reference id",
+		},
+		{
+			name:          "Number for step",
+			input:         ConditionInput{Type: "questProgress", Operator: ">=", Value: 1, ReferenceId: 1001, StepParam: "count"},
+			errorContains: "parameter count must be a string",
+		},
+		{
+			name:          "Literal and placeholder value",
+			input:         ConditionInput{Type: "level", Operator: ">=", Value: 10, ValueParam: "count"},
+			errorContains: "value and valueParam are mutually exclusive",
+		},
+		{
+			name:          "Missing parameter in group",
+			input:         ConditionInput{All: []ConditionInput{{Type: "level", Operator: ">=", Value: 10}, {Type: "fame", Operator: ">=", ValueParam: "fame"}}},
+			errorContains: "all[1]: missing parameter: fame",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := BindParameters(parameters)(tt.input); err == nil || !strings.Contains(err.Error(), tt.errorContains) {
+				t.Errorf("Expected error containing '%s', got '%v'", tt.errorContains, err)
+			}
+			if _, err := NewConditionBuilder().SetParameters(parameters).FromInput(tt.input).Build(); err == nil || !strings.Contains(err.Error(), tt.errorContains) {
+				t.Errorf("Expected builder error containing '%s', got '%v'", tt.errorContains, err)
+			}
+		})
+	}
+}
+
+// TestValidateConditionInput_Placeholders tests that unbound placeholders are accepted for storage
+func TestValidateConditionInput_Placeholders(t *testing.T) {
+	valid := []ConditionInput{
+		{Type: "item", Operator: ">=", ValueParam: "count", ReferenceIdParam: "itemId"},
+		{Type: "questProgress", Operator: ">=", Value: 5, ReferenceIdParam: "questId", StepParam: "step"},
+		{Type: "hasUnclaimedMarriageGifts", Operator: "=", ValueParam: "gifts"},
+		{Expression: "questStatus[$questId]=$status"},
+	}
+	for _, input := range valid {
+		if err := validateConditionInput(input); err != nil {
+			t.Errorf("Unexpected error for %+v: %v", input, err)
+		}
+	}
+
+	if err := validateConditionInput(ConditionInput{Type: "jobId", Operator: "in", Values: []int{100}, ValueParam: "job"}); err == nil || !strings.Contains(err.Error(), "valueParam is not supported by the in operator") {
+		t.Errorf("Expected valueParam operator error, got %v", err)
+	}
+}
+
+// TestExtract_Parameters tests that request parameters are bound and the bound values validated
+func TestExtract_Parameters(t *testing.T) {
+	rm := RestModel{
+		Id:         12345,
+		Conditions: []ConditionInput{{Type: "guildRank", Operator: "<=", ValueParam: "rank"}},
+		Parameters: Parameters{"rank": float64(2)},
+	}
+	_, conditions, err := Extract(rm)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if conditions[0].Value != 2 || conditions[0].ValueParam != "" {
+		t.Errorf("Expected bound value 2, got %+v", conditions[0])
+	}
+
+	rm.Parameters = Parameters{"rank": float64(9)}
+	if _, _, err := Extract(rm); err == nil || !strings.Contains(err.Error(), "guild rank value must be between 0 and 5") {
+		t.Errorf("Expected bound value to be validated, got %v", err)
+	}
+
+	rm.Parameters = nil
+	if _, _, err := Extract(rm); err == nil || !strings.Contains(err.Error(), "condition 0: missing parameter: rank") {
+		t.Errorf("Expected missing parameter error, got %v", err)
+	}
+}
+
+// TestWriteInvalidConditions tests that a rejected parameter is reported with the condition index, parameter name and
+// expected type
+func TestWriteInvalidConditions(t *testing.T) {
+	rm := RestModel{
+		Id: 123,
+		Conditions: []ConditionInput{
+			{Type: "level", Operator: ">=", Value: 10},
+			{Type: "item", Operator: ">=", ValueParam: "count", ReferenceId: 2000001},
+		},
+		Parameters: Parameters{"count": "ten"},
+	}
+	_, _, err := Extract(rm)
+	if err == nil {
+		t.Fatalf("Expected an error")
+	}
+
+	w := httptest.NewRecorder()
+	writeInvalidConditions(logrus.New(), w, err)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	var document struct {
+		Errors []struct {
+			Status string `json:"status"`
+			Detail string `json:"detail"`
+			Source struct {
+				Pointer string `json:"pointer"`
+			} `json:"source"`
+			Meta map[string]interface{} `json:"meta"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &document); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(document.Errors) != 1 {
+		t.Fatalf("Expected one error, got %+v", document.Errors)
+	}
+	e := document.Errors[0]
+	if e.Status != "400" || e.Detail != "condition 1: parameter count must be an integer, got ten" || e.Source.Pointer != "/data/attributes/conditions/1" {
+		t.Errorf("Error = %+v", e)
+	}
+	if e.Meta["index"] != float64(1) || e.Meta["parameter"] != "count" || e.Meta["expected"] != "integer" {
+		t.Errorf("Meta = %v", e.Meta)
+	}
+}
@@ -0,0 +1,91 @@
+package validation
+
+import (
+	"atlas-query-aggregator/asset"
+	"atlas-query-aggregator/character"
+	"fmt"
+
+	inventory2 "github.com/Chronicle20/atlas-constants/inventory"
+)
+
+// pets returns the reference data of the pets held in the cash compartment, narrowed to a template when one is given
+func pets(m character.Model, templateId uint32) []asset.PetReferenceData {
+	var result []asset.PetReferenceData
+	for _, a := range m.Inventory().CompartmentByType(inventory2.TypeValueCash).Assets() {
+		if !a.IsPet() || (templateId != 0 && a.TemplateId() != templateId) {
+			continue
+		}
+		if data, ok := a.ReferenceData().(asset.PetReferenceData); ok {
+			result = append(result, data)
+		}
+	}
+	return result
+}
+
+// summoned returns whether the pet occupies a summon slot. Pets that are not summoned have a slot of -1.
+func summoned(p asset.PetReferenceData) bool {
+	return p.Slot() >= 0
+}
+
+// petOwnedEvaluator yields the number of pets of the referenced template held
+var petOwnedEvaluator = NewEvaluator(func(c Condition, ctx ValidationContext) Evaluation {
+	return Evaluation{
+		ActualValue: len(pets(ctx.Character(), c.referenceId)),
+		Description: fmt.Sprintf("Pet %d owned %s", c.referenceId, c.Expectation()),
+		ItemId:      c.referenceId,
+	}
+}, CharactersSource, InventorySource)
+
+// petsSummonedEvaluator yields the number of summoned pets
+var petsSummonedEvaluator = NewEvaluator(func(c Condition, ctx ValidationContext) Evaluation {
+	count := 0
+	for _, p := range pets(ctx.Character(), 0) {
+		if summoned(p) {
+			count++
+		}
+	}
+	return Evaluation{ActualValue: count, Description: fmt.Sprintf("Summoned Pets %s", c.Expectation())}
+}, CharactersSource, InventorySource)
+
+// petMaximum creates an evaluator yielding the highest value among the pets of the referenced template, or 0 when
+// none are held
+func petMaximum(label string, value func(asset.PetReferenceData) int) Evaluator {
+	return NewEvaluator(func(c Condition, ctx ValidationContext) Evaluation {
+		maximum := 0
+		for _, p := range pets(ctx.Character(), c.referenceId) {
+			maximum = max(maximum, value(p))
+		}
+		return Evaluation{
+			ActualValue: maximum,
+			Description: fmt.Sprintf("Pet %d %s %s", c.referenceId, label, c.Expectation()),
+			ItemId:      c.referenceId,
+		}
+	}, CharactersSource, InventorySource)
+}
+
+// petFullnessEvaluator yields the lowest fullness among the pets of the referenced template, or among the summoned
+// pets when no template is referenced. The condition fails when there is no such pet.
+var petFullnessEvaluator = NewEvaluator(func(c Condition, ctx ValidationContext) Evaluation {
+	subject := "Summoned Pet"
+	if c.referenceId != 0 {
+		subject = fmt.Sprintf("Pet %d", c.referenceId)
+	}
+
+	lowest, found := 0, false
+	for _, p := range pets(ctx.Character(), c.referenceId) {
+		if c.referenceId == 0 && !summoned(p) {
+			continue
+		}
+		if !found || int(p.Fullness()) < lowest {
+			lowest, found = int(p.Fullness()), true
+		}
+	}
+	if !found {
+		return Evaluation{Description: fmt.Sprintf("%s fullness %s (no such pet)", subject, c.Expectation()), ItemId: c.referenceId, Unavailable: true}
+	}
+	return Evaluation{
+		ActualValue: lowest,
+		Description: fmt.Sprintf("%s fullness %s", subject, c.Expectation()),
+		ItemId:      c.referenceId,
+	}
+}, CharactersSource, InventorySource)
@@ -0,0 +1,72 @@
+package validation
+
+import (
+	"atlas-query-aggregator/asset"
+	"atlas-query-aggregator/character"
+	"atlas-query-aggregator/compartment"
+	"atlas-query-aggregator/inventory"
+	"testing"
+
+	inventory_type "github.com/Chronicle20/atlas-constants/inventory"
+	"github.com/google/uuid"
+)
+
+// createTestPets creates a character holding two summoned brown kittens and an unsummoned white puppy
+func createTestPets() character.Model {
+	compartmentId := uuid.New()
+	pet := func(id uint32, templateId uint32, level byte, closeness uint16, fullness byte, summonSlot int8) asset.Model[any] {
+		data := asset.NewPetReferenceDataBuilder().
+			SetLevel(level).
+			SetCloseness(closeness).
+			SetFullness(fullness).
+			SetSlot(summonSlot).
+			Build()
+		return asset.NewBuilder[any](id, compartmentId, templateId, id, asset.ReferenceTypePet).
+			SetSlot(int16(id)).
+			SetReferenceData(data).
+			Build()
+	}
+
+	cash := compartment.NewBuilder(compartmentId, 1, inventory_type.TypeValueCash, 96).
+		AddAsset(pet(1, 5000000, 12, 800, 40, 0)).
+		AddAsset(pet(2, 5000000, 15, 1200, 90, 1)).
+		AddAsset(pet(3, 5000001, 30, 30000, 10, -1)).
+		Build()
+
+	return character.NewModelBuilder().SetId(1).SetInventory(inventory.NewBuilder(1).SetCash(cash).Build()).Build()
+}
+
+// TestCondition_Evaluate_Pets tests the pet ownership, summon, level, closeness and fullness conditions
+func TestCondition_Evaluate_Pets(t *testing.T) {
+	char := createTestPets()
+
+	tests := []struct {
+		name            string
+		expression      string
+		wantPassed      bool
+		wantActual      int
+		wantDescription string
+	}{
+		{name: "Owned template", expression: "petOwned[5000000]>=1", wantPassed: true, wantActual: 2, wantDescription: "Pet 5000000 owned >= 1"},
+		{name: "Template not owned", expression: "petOwned[5000002]>=1", wantPassed: false, wantActual: 0, wantDescription: "Pet 5000002 owned >= 1"},
+		{name: "Summoned pets", expression: "petsSummoned=2", wantPassed: true, wantActual: 2, wantDescription: "Summoned Pets = 2"},
+		{name: "Highest level of template", expression: "petLevel[5000000]>=15", wantPassed: true, wantActual: 15, wantDescription: "Pet 5000000 level >= 15"},
+		{name: "Highest closeness of template", expression: "petCloseness[5000001]>=30000", wantPassed: true, wantActual: 30000, wantDescription: "Pet 5000001 closeness >= 30000"},
+		{name: "Lowest fullness of summoned pets", expression: "petFullness<50", wantPassed: true, wantActual: 40, wantDescription: "Summoned Pet fullness < 50"},
+		{name: "Lowest fullness of template", expression: "petFullness[5000001]<50", wantPassed: true, wantActual: 10, wantDescription: "Pet 5000001 fullness < 50"},
+		{name: "Fullness without such a pet", expression: "petFullness[5000002]<50", wantPassed: false, wantActual: 0, wantDescription: "Pet 5000002 fullness < 50 (no such pet)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, err := NewConditionBuilder().FromExpression(tt.expression).Build()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			result := condition.Evaluate(char)
+			if result.Passed != tt.wantPassed || result.ActualValue != tt.wantActual || result.Description != tt.wantDescription {
+				t.Errorf("Evaluate() = %+v, want passed %v, actual %d, description %q", result, tt.wantPassed, tt.wantActual, tt.wantDescription)
+			}
+		})
+	}
+}
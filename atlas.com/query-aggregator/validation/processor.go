@@ -6,15 +6,33 @@ import (
 	"atlas-query-aggregator/marriage"
 	"atlas-query-aggregator/quest"
 	"context"
+	"errors"
 	"fmt"
+	"github.com/Chronicle20/atlas-constants/world"
 	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
+	"slices"
+	"time"
 )
 
+// ErrConditionSetNotFound is returned when a referenced condition set or version does not exist
+var ErrConditionSetNotFound = errors.New("condition set not found")
+
+// ConditionSetResolver resolves a stored condition set into its conditions and the version resolved.
+// A version of 0 resolves the latest version.
+type ConditionSetResolver func(id uuid.UUID, version uint32) ([]ConditionInput, uint32, error)
+
+// ConditionSetResolverProvider creates a ConditionSetResolver scoped to a request
+type ConditionSetResolverProvider func(l logrus.FieldLogger, ctx context.Context) ConditionSetResolver
+
 type Processor interface {
 	// ValidateStructured validates a list of structured condition inputs against a character
 	ValidateStructured(decorators ...model.Decorator[ValidationResult]) func(characterId uint32, conditionInputs []ConditionInput) (ValidationResult, error)
-	
+
+	// ValidateAccount validates a list of structured condition inputs against every character an account has in a world
+	ValidateAccount(decorators ...model.Decorator[ValidationResult]) func(accountId uint32, worldId world.Id, scope AccountScope, conditionInputs []ConditionInput) (AccountValidationResult, error)
+
 	// ValidateWithContext validates a list of structured condition inputs using a validation context
 	ValidateWithContext(decorators ...model.Decorator[ValidationResult]) func(ctx ValidationContext, conditionInputs []ConditionInput) (ValidationResult, error)
 }
@@ -27,78 +45,118 @@ type ProcessorImpl struct {
 	inventoryProcessor inventory.Processor
 	questProcessor     quest.Processor
 	marriageProcessor  marriage.Processor
+	configuration      ConfigurationResolver // Resolves the tenant configuration; nil evaluates against an empty one
+	clock              func() time.Time      // Reads the server time conditions are evaluated at; nil reads time.Now
+}
+
+// ProcessorOption configures a validation processor created by NewProcessor
+type ProcessorOption func(p *ProcessorImpl)
+
+// WithClock makes the processor read the server time conditions are evaluated at from the clock rather than time.Now
+func WithClock(clock func() time.Time) ProcessorOption {
+	return func(p *ProcessorImpl) {
+		p.clock = clock
+	}
 }
 
-// NewProcessor creates a new validation processor
-func NewProcessor(l logrus.FieldLogger, ctx context.Context) Processor {
-	return &ProcessorImpl{
+// NewProcessor creates a new validation processor reading the tenant configuration from the resolver
+func NewProcessor(l logrus.FieldLogger, ctx context.Context, configuration ConfigurationResolver, options ...ProcessorOption) Processor {
+	p := &ProcessorImpl{
 		l:                  l,
 		ctx:                ctx,
 		characterProcessor: character.NewProcessor(l, ctx),
 		inventoryProcessor: inventory.NewProcessor(l, ctx),
 		questProcessor:     quest.NewProcessor(l, ctx),
 		marriageProcessor:  marriage.NewProcessor(l, ctx),
+		configuration:      configuration,
 	}
+	for _, option := range options {
+		option(p)
+	}
+	return p
 }
 
-
 // ValidateStructured validates a list of structured condition inputs against a character
 func (p *ProcessorImpl) ValidateStructured(resultDecorators ...model.Decorator[ValidationResult]) func(characterId uint32, conditionInputs []ConditionInput) (ValidationResult, error) {
 	return func(characterId uint32, conditionInputs []ConditionInput) (ValidationResult, error) {
-		// Create a new validation result
-		result := NewValidationResult(characterId)
-
-		// Parse all conditions
-		conditions := make([]Condition, 0, len(conditionInputs))
-		needsInventory := false
-		needsGuild := false
-
-		for _, input := range conditionInputs {
-			condition, err := NewConditionBuilder().FromInput(input).Build()
-			if err != nil {
-				return result, fmt.Errorf("invalid condition: %w", err)
-			}
+		conditions, err := buildConditions(conditionInputs)
+		if err != nil {
+			return NewValidationResult(characterId), err
+		}
+		return p.validateCharacter(p.characterProcessor.GetById, 0, resultDecorators)(characterId, conditions)
+	}
+}
 
-			conditions = append(conditions, condition)
+// ValidateAccount validates a list of structured condition inputs against every character an account has in a world,
+// combining the character results using the scope
+func (p *ProcessorImpl) ValidateAccount(resultDecorators ...model.Decorator[ValidationResult]) func(accountId uint32, worldId world.Id, scope AccountScope, conditionInputs []ConditionInput) (AccountValidationResult, error) {
+	return func(accountId uint32, worldId world.Id, scope AccountScope, conditionInputs []ConditionInput) (AccountValidationResult, error) {
+		conditions, err := buildConditions(conditionInputs)
+		if err != nil {
+			return NewAccountValidationResult(accountId, scope, nil), err
+		}
 
-			// Check if this condition requires inventory data
-			if condition.conditionType == ItemCondition {
-				needsInventory = true
-			}
+		// The characters are fetched once for the account, so that request is what explain reports for each of them
+		start := time.Now()
+		characters, err := p.characterProcessor.GetByAccountAndWorld()(accountId, worldId)
+		fetched := time.Since(start)
+		if err != nil {
+			return NewAccountValidationResult(accountId, scope, nil), fmt.Errorf("failed to get account characters: %w", err)
+		}
 
-			// Check if this condition requires guild data
-			if condition.conditionType == GuildLeaderCondition {
-				needsGuild = true
+		results := make([]ValidationResult, 0, len(characters))
+		for _, c := range characters {
+			result, err := p.validateCharacter(fetchedCharacter(c), fetched, resultDecorators)(c.Id(), conditions)
+			if err != nil {
+				return NewAccountValidationResult(accountId, scope, nil), err
 			}
+			results = append(results, result)
 		}
+		return NewAccountValidationResult(accountId, scope, results), nil
+	}
+}
 
-		// Get character data with inventory and/or guild if needed
-		var characterData character.Model
-		var err error
-		var charDecorators []model.Decorator[character.Model]
-
-		if needsInventory {
-			charDecorators = append(charDecorators, p.characterProcessor.InventoryDecorator)
+// buildConditions parses a list of structured condition inputs
+func buildConditions(conditionInputs []ConditionInput) ([]Condition, error) {
+	conditions := make([]Condition, 0, len(conditionInputs))
+	for _, input := range conditionInputs {
+		condition, err := NewConditionBuilder().FromInput(input).Build()
+		if err != nil {
+			return nil, fmt.Errorf("invalid condition: %w", err)
 		}
+		conditions = append(conditions, condition)
+	}
+	return conditions, nil
+}
 
-		if needsGuild {
-			charDecorators = append(charDecorators, p.characterProcessor.GuildDecorator)
-		}
+// characterGetter retrieves a character, applying decorators to it
+type characterGetter func(decorators ...model.Decorator[character.Model]) func(characterId uint32) (character.Model, error)
 
-		if len(charDecorators) > 0 {
-			characterData, err = p.characterProcessor.GetById(charDecorators...)(characterId)
-		} else {
-			characterData, err = p.characterProcessor.GetById()(characterId)
+// fetchedCharacter returns a characterGetter decorating an already retrieved character
+func fetchedCharacter(m character.Model) characterGetter {
+	return func(decorators ...model.Decorator[character.Model]) func(characterId uint32) (character.Model, error) {
+		return func(characterId uint32) (character.Model, error) {
+			return model.Map(model.Decorate(decorators))(model.FixedProvider(m))()
 		}
+	}
+}
 
+// validateCharacter evaluates conditions against a character retrieved by the getter. The time already spent retrieving
+// the character, for one retrieved before the getter is called, is given as fetched.
+func (p *ProcessorImpl) validateCharacter(get characterGetter, fetched time.Duration, resultDecorators []model.Decorator[ValidationResult]) func(characterId uint32, conditions []Condition) (ValidationResult, error) {
+	return func(characterId uint32, conditions []Condition) (ValidationResult, error) {
+		// Create a new validation result
+		result := NewValidationResult(characterId)
+
+		ctx, reports, err := p.loadContext(get, fetched, characterId, fetchPlan(conditions...))
 		if err != nil {
-			return result, fmt.Errorf("failed to get character data: %w", err)
+			return result, err
 		}
 
-		// Evaluate each condition
+		// Evaluate each condition at the same server time
 		for _, condition := range conditions {
-			conditionResult := condition.Evaluate(characterData)
-			result.AddConditionResult(conditionResult)
+			conditionResult := condition.explain(condition.EvaluateWithContext(ctx), reports)
+			result.AddWeightedConditionResult(conditionResult, condition.Weight())
 		}
 
 		// Apply decorators
@@ -108,6 +166,91 @@ func (p *ProcessorImpl) ValidateStructured(resultDecorators ...model.Decorator[V
 	}
 }
 
+// loadContext fetches the data sources of the plan for a character retrieved by the getter, recording the outcome of
+// each fetch. The time already spent retrieving the character is given as fetched. Only a failure to retrieve the
+// character is returned as an error.
+func (p *ProcessorImpl) loadContext(get characterGetter, fetched time.Duration, characterId uint32, plan []DataSource) (ValidationContext, fetchReports, error) {
+	// Decorate the character with the data the plan holds, recording the outcome of each fetch
+	var charDecorators []model.Decorator[character.Model]
+	reports := make(fetchReports)
+	fetchers := p.characterFetchers()
+	for _, source := range plan {
+		if fetch, ok := fetchers[source]; ok {
+			charDecorators = append(charDecorators, reports.decorator(source, fetch))
+		}
+	}
+
+	start := time.Now()
+	characterData, err := get(charDecorators...)(characterId)
+	if err != nil {
+		return ValidationContext{}, reports, fmt.Errorf("failed to get character data: %w", err)
+	}
+	reports[CharactersSource] = fetchReport{duration: fetched + time.Since(start) - reports.total()}
+
+	// Quests, marriage data and the tenant configuration are not carried by the character. Each is only read when the
+	// plan holds it.
+	ctx := characterContext(characterData).WithNow(p.now())
+	if slices.Contains(plan, QuestsSource) {
+		start = time.Now()
+		quests, err := p.questProcessor.GetQuests(characterId)()
+		reports[QuestsSource] = fetchReport{duration: time.Since(start), err: err}
+		if err == nil {
+			ctx = ctx.WithQuests(quests)
+		}
+	}
+	if slices.Contains(plan, MarriageSource) {
+		start = time.Now()
+		marriageData, err := p.marriageProcessor.GetMarriageGifts(characterId)()
+		reports[MarriageSource] = fetchReport{duration: time.Since(start), err: err}
+		if err == nil {
+			ctx = ctx.WithMarriage(marriageData)
+		}
+	}
+	if slices.Contains(plan, ConfigurationSource) {
+		start = time.Now()
+		configuration, err := p.resolveConfiguration()
+		reports[ConfigurationSource] = fetchReport{duration: time.Since(start), err: err}
+		if err == nil {
+			ctx = ctx.WithConfiguration(configuration)
+		}
+	}
+
+	// A failed fetch leaves its source withheld, so the conditions reading it fail rather than being evaluated against
+	// defaults
+	for source, report := range reports {
+		if report.err != nil {
+			p.l.WithError(report.err).Warnf("Unable to retrieve [%s] data for character [%d]. Dependent conditions fail as data unavailable.", source, characterId)
+			ctx = ctx.withUnavailable(source)
+		}
+	}
+	return ctx, reports, nil
+}
+
+// resolveConfiguration resolves the configuration of the requesting tenant
+func (p *ProcessorImpl) resolveConfiguration() (TenantConfiguration, error) {
+	if p.configuration == nil {
+		return TenantConfiguration{}, nil
+	}
+	return p.configuration()
+}
+
+// now reads the server time conditions are evaluated at
+func (p *ProcessorImpl) now() time.Time {
+	if p.clock == nil {
+		return time.Now()
+	}
+	return p.clock()
+}
+
+// characterFetchers returns the fallible character decorations supplying each data source carried by the character
+// model. Sources absent from the map are added to the ValidationContext instead.
+func (p *ProcessorImpl) characterFetchers() map[DataSource]func(character.Model) (character.Model, error) {
+	return map[DataSource]func(character.Model) (character.Model, error){
+		InventorySource: p.characterProcessor.WithInventory,
+		GuildsSource:    p.characterProcessor.WithGuild,
+	}
+}
+
 // ValidateWithContext validates a list of structured condition inputs using a validation context
 func (p *ProcessorImpl) ValidateWithContext(decorators ...model.Decorator[ValidationResult]) func(ctx ValidationContext, conditionInputs []ConditionInput) (ValidationResult, error) {
 	return func(ctx ValidationContext, conditionInputs []ConditionInput) (ValidationResult, error) {
@@ -115,20 +258,15 @@ func (p *ProcessorImpl) ValidateWithContext(decorators ...model.Decorator[Valida
 		result := NewValidationResult(ctx.Character().Id())
 
 		// Parse all conditions
-		conditions := make([]Condition, 0, len(conditionInputs))
-
-		for _, input := range conditionInputs {
-			condition, err := NewConditionBuilder().FromInput(input).Build()
-			if err != nil {
-				return result, fmt.Errorf("invalid condition: %w", err)
-			}
-			conditions = append(conditions, condition)
+		conditions, err := buildConditions(conditionInputs)
+		if err != nil {
+			return result, err
 		}
 
 		// Evaluate each condition using the context
 		for _, condition := range conditions {
 			conditionResult := condition.EvaluateWithContext(ctx)
-			result.AddConditionResult(conditionResult)
+			result.AddWeightedConditionResult(conditionResult, condition.Weight())
 		}
 
 		// Apply decorators
@@ -138,23 +276,18 @@ func (p *ProcessorImpl) ValidateWithContext(decorators ...model.Decorator[Valida
 	}
 }
 
-// GetValidationContextProvider returns a provider that can create validation contexts
-func (p *ProcessorImpl) GetValidationContextProvider() ValidationContextProvider {
-	return NewContextBuilderProvider(
-		func(characterId uint32) model.Provider[character.Model] {
-			return func() (character.Model, error) {
-				return p.characterProcessor.GetById(p.characterProcessor.InventoryDecorator)(characterId)
-			}
-		},
-		func(characterId uint32) model.Provider[map[uint32]quest.Model] {
-			return func() (map[uint32]quest.Model, error) {
-				// For now, return empty map since quest service is not fully implemented
-				// In a real implementation, this would fetch all quests for the character
-				return make(map[uint32]quest.Model), nil
+// GetValidationContextProvider returns a provider of validation contexts holding the data the conditions read, fetched
+// as ValidateStructured fetches it. Only the sources the conditions read are loaded, so the conditions the contexts are
+// for must be given.
+func (p *ProcessorImpl) GetValidationContextProvider(conditionInputs []ConditionInput) ValidationContextProvider {
+	return contextProviderFunc(func(characterId uint32) model.Provider[ValidationContext] {
+		return func() (ValidationContext, error) {
+			conditions, err := buildConditions(conditionInputs)
+			if err != nil {
+				return ValidationContext{}, err
 			}
-		},
-		func(characterId uint32) model.Provider[marriage.Model] {
-			return p.marriageProcessor.GetMarriageGifts(characterId)
-		},
-	)
+			ctx, _, err := p.loadContext(p.characterProcessor.GetById, 0, characterId, fetchPlan(conditions...))
+			return ctx, err
+		}
+	})
 }
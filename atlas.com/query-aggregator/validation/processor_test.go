@@ -22,7 +22,6 @@ import (
 	"time"
 )
 
-
 // TestValidateConditions tests the condition validation logic directly
 // Helper function to create a test guild for processor tests
 func createTestGuild(id uint32, leaderId uint32) guild.Model {
@@ -342,6 +341,40 @@ func TestProcessorValidateStructured(t *testing.T) {
 			wantDetailsCount: 1,
 			wantError:        false,
 		},
+		{
+			name:        "Nested item condition in group - pass",
+			characterId: 123,
+			conditions: []ConditionInput{
+				{Any: []ConditionInput{
+					{Type: "jobId", Operator: "=", Value: 200},
+					{Type: "item", Operator: ">=", Value: 10, ReferenceId: 2000001},
+				}},
+			},
+			setupMock: func(m *mock.ProcessorImpl) {
+				m.GetByIdFunc = func(decorators ...model.Decorator[character.Model]) func(characterId uint32) (character.Model, error) {
+					return func(characterId uint32) (character.Model, error) {
+						char := character.NewModelBuilder().
+							SetId(characterId).
+							Build()
+						for _, decorator := range decorators {
+							char = decorator(char)
+						}
+						return char, nil
+					}
+				}
+
+				// The inventory must be loaded even though the item condition is nested
+				m.InventoryDecoratorFunc = func(m character.Model) character.Model {
+					return character.NewModelBuilder().
+						SetId(m.Id()).
+						SetInventory(createTestInventory(m.Id())).
+						Build()
+				}
+			},
+			wantPassed:       true,
+			wantDetailsCount: 1,
+			wantError:        false,
+		},
 		{
 			name:        "Guild leader condition - pass",
 			characterId: 123,
@@ -423,7 +456,7 @@ func TestProcessorValidateStructured(t *testing.T) {
 				// Mock the GuildDecorator to add a guild with a different leader
 				m.GuildDecoratorFunc = func(m character.Model) character.Model {
 					// Create a guild with a different leader
-					testGuild := createTestGuild(1, m.Id() + 1)
+					testGuild := createTestGuild(1, m.Id()+1)
 					return character.NewModelBuilder().
 						SetId(m.Id()).
 						SetGuild(testGuild).
@@ -497,16 +530,16 @@ func TestValidateWithContextMockingExternalServices(t *testing.T) {
 	logger := logrus.New()
 
 	tests := []struct {
-		name                  string
-		characterId           uint32
-		conditions            []ConditionInput
-		setupCharacterMock    func(*mock.ProcessorImpl)
-		setupQuestMock        func(*questMock.ProcessorImpl)
-		setupMarriageMock     func(*marriageMock.ProcessorImpl)
-		wantPassed            bool
-		wantDetailsCount      int
-		wantError             bool
-		wantErrorContains     string
+		name               string
+		characterId        uint32
+		conditions         []ConditionInput
+		setupCharacterMock func(*mock.ProcessorImpl)
+		setupQuestMock     func(*questMock.ProcessorImpl)
+		setupMarriageMock  func(*marriageMock.ProcessorImpl)
+		wantPassed         bool
+		wantDetailsCount   int
+		wantError          bool
+		wantErrorContains  string
 	}{
 		{
 			name:        "Quest Status validation - success",
@@ -754,3 +787,244 @@ func TestValidateWithContextMockingExternalServices(t *testing.T) {
 	}
 }
 
+// TestGetValidationContextProvider_Quests tests that the context provider loads every quest of the character in one
+// request, so quest conditions resolve against the quests service, and only loads the sources the conditions read
+func TestGetValidationContextProvider_Quests(t *testing.T) {
+	requests, marriageRequests := 0, 0
+	processor := &ProcessorImpl{
+		l:   logrus.New(),
+		ctx: context.Background(),
+		characterProcessor: &mock.ProcessorImpl{
+			GetByIdFunc: func(decorators ...model.Decorator[character.Model]) func(characterId uint32) (character.Model, error) {
+				return func(characterId uint32) (character.Model, error) {
+					return character.NewModelBuilder().SetId(characterId).Build(), nil
+				}
+			},
+		},
+		questProcessor: &questMock.ProcessorImpl{
+			GetQuestsFunc: func(characterId uint32) model.Provider[map[uint32]quest.Model] {
+				return func() (map[uint32]quest.Model, error) {
+					requests++
+					return map[uint32]quest.Model{
+						1001: quest.NewModelBuilder().SetId(1001).SetStatus(quest.COMPLETED).Build(),
+						1002: quest.NewModelBuilder().SetId(1002).SetStatus(quest.STARTED).SetProgress("mobsKilled", 7).Build(),
+					}, nil
+				}
+			},
+		},
+		marriageProcessor: &marriageMock.ProcessorImpl{
+			GetMarriageGiftsFunc: func(characterId uint32) model.Provider[marriage.Model] {
+				return func() (marriage.Model, error) {
+					marriageRequests++
+					return marriage.NewModel(characterId, false), nil
+				}
+			},
+		},
+	}
+	conditions := []ConditionInput{
+		{Type: "questStatus", Operator: "=", Value: int(quest.COMPLETED), ReferenceId: 1001},
+		{Type: "questProgress", Operator: ">=", Value: 5, ReferenceId: 1002, Step: "mobsKilled"},
+		{Type: "questStatus", Operator: "=", Value: int(quest.NOT_STARTED), ReferenceId: 1003},
+	}
+
+	ctx, err := processor.GetValidationContextProvider(conditions).GetValidationContext(123)()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if requests != 1 || marriageRequests != 0 {
+		t.Errorf("Quests requested %d and marriage %d times, want 1 and 0", requests, marriageRequests)
+	}
+
+	result, err := processor.ValidateWithContext()(ctx, conditions)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i, want := range []bool{true, true, true} {
+		if r := result.Results()[i]; r.Passed != want {
+			t.Errorf("Result %d = %+v, want passed %v", i, r, want)
+		}
+	}
+
+	t.Run("Quests service failure", func(t *testing.T) {
+		processor.questProcessor = &questMock.ProcessorImpl{
+			GetQuestsFunc: func(characterId uint32) model.Provider[map[uint32]quest.Model] {
+				return func() (map[uint32]quest.Model, error) {
+					return nil, errors.New("service unavailable")
+				}
+			},
+		}
+		ctx, err := processor.GetValidationContextProvider(conditions).GetValidationContext(123)()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		result, err := processor.ValidateWithContext()(ctx, conditions)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if r := result.Results()[2]; r.Passed || r.Description != "quest status = 1 (QUESTS data unavailable)" {
+			t.Errorf("Expected quest conditions to fail as data unavailable, got %+v", r)
+		}
+	})
+}
+
+// TestProcessorValidateStructured_ContextSources tests that quest and marriage conditions are evaluated against data
+// loaded for the validation, and that only the sources the conditions need are loaded
+func TestProcessorValidateStructured_ContextSources(t *testing.T) {
+	tests := []struct {
+		name              string
+		conditions        []ConditionInput
+		wantQuestFetches  int
+		wantMarriageFetch int
+		wantPassed        []bool
+	}{
+		{
+			name:       "Character only",
+			conditions: []ConditionInput{{Type: "level", Operator: ">=", Value: 10}},
+			wantPassed: []bool{true},
+		},
+		{
+			name: "Quests",
+			conditions: []ConditionInput{
+				{Type: "questStatus", Operator: "=", Value: int(quest.COMPLETED), ReferenceId: 1001},
+				{Type: "questProgress", Operator: ">=", Value: 5, ReferenceId: 1002, Step: "mobsKilled"},
+			},
+			wantQuestFetches: 1,
+			wantPassed:       []bool{true, true},
+		},
+		{
+			name: "Quests and marriage",
+			conditions: []ConditionInput{
+				{Type: "questStatus", Operator: "=", Value: int(quest.STARTED), ReferenceId: 1001},
+				{Type: "hasUnclaimedMarriageGifts", Operator: "=", Value: 1},
+			},
+			wantQuestFetches:  1,
+			wantMarriageFetch: 1,
+			wantPassed:        []bool{false, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			questFetches, marriageFetches := 0, 0
+			processor := &ProcessorImpl{
+				l:   logrus.New(),
+				ctx: context.Background(),
+				characterProcessor: &mock.ProcessorImpl{
+					GetByIdFunc: func(decorators ...model.Decorator[character.Model]) func(characterId uint32) (character.Model, error) {
+						return func(characterId uint32) (character.Model, error) {
+							return character.NewModelBuilder().SetId(characterId).SetLevel(50).Build(), nil
+						}
+					},
+				},
+				questProcessor: &questMock.ProcessorImpl{
+					GetQuestsFunc: func(characterId uint32) model.Provider[map[uint32]quest.Model] {
+						return func() (map[uint32]quest.Model, error) {
+							questFetches++
+							return map[uint32]quest.Model{
+								1001: quest.NewModelBuilder().SetId(1001).SetStatus(quest.COMPLETED).Build(),
+								1002: quest.NewModelBuilder().SetId(1002).SetStatus(quest.STARTED).SetProgress("mobsKilled", 7).Build(),
+							}, nil
+						}
+					},
+				},
+				marriageProcessor: &marriageMock.ProcessorImpl{
+					GetMarriageGiftsFunc: func(characterId uint32) model.Provider[marriage.Model] {
+						return func() (marriage.Model, error) {
+							marriageFetches++
+							return marriage.NewModel(characterId, true), nil
+						}
+					},
+				},
+			}
+
+			result, err := processor.ValidateStructured()(123, tt.conditions)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if questFetches != tt.wantQuestFetches || marriageFetches != tt.wantMarriageFetch {
+				t.Errorf("Fetched quests %d and marriage %d times, want %d and %d", questFetches, marriageFetches, tt.wantQuestFetches, tt.wantMarriageFetch)
+			}
+			for i, want := range tt.wantPassed {
+				if r := result.Results()[i]; r.Passed != want {
+					t.Errorf("Result %d = %+v, want passed %v", i, r, want)
+				}
+			}
+		})
+	}
+}
+
+// TestProcessorValidateStructured_FailedFetches tests that conditions reading a source whose fetch failed fail as data
+// unavailable rather than being evaluated against defaults, while other conditions are unaffected
+func TestProcessorValidateStructured_FailedFetches(t *testing.T) {
+	processor := &ProcessorImpl{
+		l:   logrus.New(),
+		ctx: context.Background(),
+		characterProcessor: &mock.ProcessorImpl{
+			GetByIdFunc: func(decorators ...model.Decorator[character.Model]) func(characterId uint32) (character.Model, error) {
+				return func(characterId uint32) (character.Model, error) {
+					char := character.NewModelBuilder().SetId(characterId).SetLevel(50).Build()
+					for _, decorator := range decorators {
+						char = decorator(char)
+					}
+					return char, nil
+				}
+			},
+			WithInventoryFunc: func(m character.Model) (character.Model, error) {
+				return m, errors.New("inventory service unavailable")
+			},
+		},
+		questProcessor: &questMock.ProcessorImpl{
+			GetQuestsFunc: func(characterId uint32) model.Provider[map[uint32]quest.Model] {
+				return func() (map[uint32]quest.Model, error) {
+					return nil, errors.New("quest service unavailable")
+				}
+			},
+		},
+		marriageProcessor: &marriageMock.ProcessorImpl{
+			GetMarriageGiftsFunc: func(characterId uint32) model.Provider[marriage.Model] {
+				return func() (marriage.Model, error) {
+					return marriage.Model{}, errors.New("marriage service unavailable")
+				}
+			},
+		},
+		configuration: func() (TenantConfiguration, error) {
+			return TenantConfiguration{}, errors.New("database closed")
+		},
+	}
+
+	// Each failing condition would pass against the defaults of its source
+	conditions := []ConditionInput{
+		{Type: "level", Operator: ">=", Value: 10},
+		{Type: "questStatus", Operator: "=", Value: int(quest.NOT_STARTED), ReferenceId: 1001},
+		{Type: "questForfeitCount", Operator: "=", Value: 0, ReferenceId: 1001},
+		{Type: "hasUnclaimedMarriageGifts", Operator: "=", Value: 0},
+		{Type: "item", Operator: "<", Value: 1, ReferenceId: 2000001},
+		{Type: "hourRange", Operator: "between", Min: intPtr(0), Max: intPtr(23)},
+		{Type: "mapIn", Operator: "notIn", Values: []int{100000000}},
+	}
+	result, err := processor.ValidateStructured()(123, conditions)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		wantPassed      bool
+		wantDescription string
+	}{
+		{wantPassed: true, wantDescription: "Level >= 10"},
+		{wantPassed: false, wantDescription: "quest status = 1 (QUESTS data unavailable)"},
+		{wantPassed: false, wantDescription: "quest forfeits = 0 (QUESTS data unavailable)"},
+		{wantPassed: false, wantDescription: "marriage gift = 0 (MARRIAGE data unavailable)"},
+		{wantPassed: false, wantDescription: "item < 1 (INVENTORY data unavailable)"},
+		{wantPassed: false, wantDescription: "hour between 0 and 23 (CONFIGURATION data unavailable)"},
+		{wantPassed: true, wantDescription: "Map notIn [100000000]"},
+	}
+	for i, tt := range tests {
+		if r := result.Results()[i]; r.Passed != tt.wantPassed || r.Description != tt.wantDescription {
+			t.Errorf("Result %d = %+v, want passed %v, description %q", i, r, tt.wantPassed, tt.wantDescription)
+		}
+	}
+	if result.Passed() {
+		t.Error("Expected the validation to fail")
+	}
+}
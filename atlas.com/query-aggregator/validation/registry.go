@@ -0,0 +1,535 @@
+package validation
+
+import (
+	"atlas-query-aggregator/asset"
+	"atlas-query-aggregator/character"
+	"atlas-query-aggregator/equipment"
+	"atlas-query-aggregator/guild"
+	"atlas-query-aggregator/quest"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	inventory2 "github.com/Chronicle20/atlas-constants/inventory"
+	"github.com/Chronicle20/atlas-constants/inventory/slot"
+)
+
+// ConditionTypeDefinition describes a supported condition type: the operators and values it accepts, the fields it
+// requires and the upstream data it is evaluated against
+type ConditionTypeDefinition struct {
+	conditionType  ConditionType
+	name           string                         // Human readable name used in descriptions and errors, e.g. "quest progress"
+	operators      []Operator                     // Supported operators; nil supports every operator
+	minValue       *int                           // Inclusive lower bound of the expected values, if any
+	maxValue       *int                           // Inclusive upper bound of the expected values, if any
+	reference      string                         // What the referenceId identifies, e.g. "item"; empty when no referenceId is accepted
+	step           string                         // What the step identifies; empty when no step is accepted
+	optional       qualifiers                     // Accepted qualifiers that may be omitted
+	stepValues     string                         // Configuration field of the list a step expands to, given in place of values; {step} is substituted
+	wraps          bool                           // Between ranges may wrap past the largest value to the smallest, e.g. [22, 2]
+	checkReference func(referenceId uint32) error // Checks a referenceId given to the type; nil accepts any referenceId
+	checkStep      func(step string) error        // Checks a step given to the type; nil accepts any step
+	source         DataSource
+	field          string    // Field of the source holding the actual value; {referenceId} and {step} are substituted
+	evaluator      Evaluator // Determines the actual value of conditions of the type
+}
+
+// qualifiers flags the referenceId and step of a condition
+type qualifiers struct {
+	referenceId bool
+	step        bool
+}
+
+// Type returns the condition type
+func (d ConditionTypeDefinition) Type() ConditionType {
+	return d.conditionType
+}
+
+// Name returns the human readable name of the condition type
+func (d ConditionTypeDefinition) Name() string {
+	return d.name
+}
+
+// Operators returns the operators supported by the condition type
+func (d ConditionTypeDefinition) Operators() []Operator {
+	if d.operators == nil {
+		return operators
+	}
+	return d.operators
+}
+
+// MinValue returns the inclusive lower bound of the expected values, or nil when unbounded
+func (d ConditionTypeDefinition) MinValue() *int {
+	return d.minValue
+}
+
+// MaxValue returns the inclusive upper bound of the expected values, or nil when unbounded
+func (d ConditionTypeDefinition) MaxValue() *int {
+	return d.maxValue
+}
+
+// RequiresReferenceId returns whether conditions of the type must specify a referenceId
+func (d ConditionTypeDefinition) RequiresReferenceId() bool {
+	return d.reference != "" && !d.optional.referenceId
+}
+
+// OptionalReferenceId returns whether conditions of the type may, but need not, specify a referenceId
+func (d ConditionTypeDefinition) OptionalReferenceId() bool {
+	return d.reference != "" && d.optional.referenceId
+}
+
+// RequiresStep returns whether conditions of the type must specify a step
+func (d ConditionTypeDefinition) RequiresStep() bool {
+	return d.step != "" && !d.optional.step
+}
+
+// OptionalStep returns whether conditions of the type may, but need not, specify a step
+func (d ConditionTypeDefinition) OptionalStep() bool {
+	return d.step != "" && d.optional.step
+}
+
+// StepValues returns the configuration field of the list of values a step may name in place of values, with a {step}
+// placeholder, or empty when the values must be listed
+func (d ConditionTypeDefinition) StepValues() string {
+	return d.stepValues
+}
+
+// Wraps returns whether between ranges of the type may wrap past the largest value to the smallest, with min greater
+// than max
+func (d ConditionTypeDefinition) Wraps() bool {
+	return d.wraps
+}
+
+// Source returns the upstream resource supplying the actual value
+func (d ConditionTypeDefinition) Source() DataSource {
+	return d.source
+}
+
+// Field returns the field of the source holding the actual value, with {referenceId} and {step} placeholders
+func (d ConditionTypeDefinition) Field() string {
+	return d.field
+}
+
+// Evaluator returns the evaluator determining the actual value of conditions of the type
+func (d ConditionTypeDefinition) Evaluator() Evaluator {
+	return d.evaluator
+}
+
+// DataSources returns every upstream resource evaluating conditions of the type may read, in reporting order
+func (d ConditionTypeDefinition) DataSources() []DataSource {
+	return d.dataSources(d.stepValues != "")
+}
+
+// sourcesFor returns every upstream resource evaluating a condition of the type with the step reads, in reporting order.
+// A step naming a list of values is read from the tenant configuration.
+func (d ConditionTypeDefinition) sourcesFor(step string) []DataSource {
+	return d.dataSources(d.stepValues != "" && step != "")
+}
+
+// dataSources returns the upstream resources the evaluator reads, and the tenant configuration when named values are
+// read from it, in reporting order
+func (d ConditionTypeDefinition) dataSources(namedValues bool) []DataSource {
+	needs := d.evaluator.Needs()
+	if namedValues {
+		needs = append(slices.Clone(needs), ConfigurationSource)
+	}
+	result := make([]DataSource, 0, len(needs))
+	for _, source := range dataSources {
+		if slices.Contains(needs, source) {
+			result = append(result, source)
+		}
+	}
+	return result
+}
+
+// fieldFor returns the field of the source holding the actual value for the referenced entity and step
+func (d ConditionTypeDefinition) fieldFor(referenceId uint32, step string) string {
+	return strings.NewReplacer("{referenceId}", strconv.FormatUint(uint64(referenceId), 10), "{step}", step).Replace(d.field)
+}
+
+// sourceFor returns the upstream resource and field supplying a condition of the type. A step naming a list of values
+// is supplied by the tenant configuration.
+func (d ConditionTypeDefinition) sourceFor(referenceId uint32, step string) (DataSource, string) {
+	if d.stepValues != "" && step != "" {
+		return ConfigurationSource, strings.ReplaceAll(d.stepValues, "{step}", step)
+	}
+	return d.source, d.fieldFor(referenceId, step)
+}
+
+// validate checks an operator, the values it is expected to compare against, and the presence of the referenceId
+// and step against the definition, returning the first problem found
+func (d ConditionTypeDefinition) validate(operator Operator, expected []int, hasReference bool, hasStep bool) error {
+	return firstError(d.problems(operator, expected, hasReference, hasStep))
+}
+
+// problems returns every problem validate would report, in the order they are checked
+func (d ConditionTypeDefinition) problems(operator Operator, expected []int, hasReference bool, hasStep bool) []error {
+	errs := make([]error, 0)
+	if !slices.Contains(d.Operators(), operator) {
+		errs = append(errs, fmt.Errorf("operator %s is not supported for %s conditions", operator, d.name))
+	}
+	if d.RequiresReferenceId() && !hasReference {
+		errs = append(errs, fmt.Errorf("referenceId is required for %s conditions", d.reference))
+	}
+	if d.RequiresStep() && !hasStep {
+		errs = append(errs, fmt.Errorf("step is required for %s conditions", d.name))
+	}
+
+	// Every out of range value breaks the same bound, so it is reported once
+	for _, value := range expected {
+		if err := d.checkValue(value); err != nil {
+			errs = append(errs, err)
+			break
+		}
+	}
+	return errs
+}
+
+// checkValue checks a single expected value against the bounds of the definition
+func (d ConditionTypeDefinition) checkValue(value int) error {
+	switch {
+	case d.minValue != nil && d.maxValue != nil:
+		if value < *d.minValue || value > *d.maxValue {
+			return fmt.Errorf("%s value must be between %d and %d", d.name, *d.minValue, *d.maxValue)
+		}
+	case d.minValue != nil && *d.minValue == 0:
+		if value < 0 {
+			return fmt.Errorf("%s value must be non-negative", d.name)
+		}
+	case d.minValue != nil:
+		if value < *d.minValue {
+			return fmt.Errorf("%s value must be at least %d", d.name, *d.minValue)
+		}
+	}
+	return nil
+}
+
+// validateQualifiers checks a referenceId and step given to a condition of the type. Absent ones are checked by validate.
+func (d ConditionTypeDefinition) validateQualifiers(referenceId uint32, step string) error {
+	return firstError(d.qualifierProblems(referenceId, step))
+}
+
+// qualifierProblems returns every problem validateQualifiers would report
+func (d ConditionTypeDefinition) qualifierProblems(referenceId uint32, step string) []error {
+	errs := make([]error, 0)
+	if referenceId != 0 && d.checkReference != nil {
+		if err := d.checkReference(referenceId); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if step != "" && d.checkStep != nil {
+		if err := d.checkStep(step); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// firstError returns the first of a list of errors, or nil when there are none
+func firstError(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs[0]
+}
+
+// slotType checks that a step names an equipment slot type, e.g. "hat"
+func slotType(step string) error {
+	for _, s := range slot.Slots {
+		if string(s.Type) == step {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported equipment slot type: %s", step)
+}
+
+// inventoryTypeNames names each inventory type a compartment holds
+var inventoryTypeNames = map[inventory2.Type]string{
+	inventory2.TypeValueEquip: "equip",
+	inventory2.TypeValueUse:   "use",
+	inventory2.TypeValueSetup: "setup",
+	inventory2.TypeValueETC:   "etc",
+	inventory2.TypeValueCash:  "cash",
+}
+
+// inventoryType checks that a referenceId identifies an inventory type, 1 (equip) through 5 (cash)
+func inventoryType(referenceId uint32) error {
+	if _, ok := inventoryTypeNames[inventory2.Type(referenceId)]; !ok {
+		return fmt.Errorf("unsupported inventory type: %d", referenceId)
+	}
+	return nil
+}
+
+// jobId checks that a referenceId fits a job ID
+func jobId(referenceId uint32) error {
+	if referenceId > math.MaxUint16 {
+		return fmt.Errorf("unsupported job: %d", referenceId)
+	}
+	return nil
+}
+
+// memberLevel checks that a referenceId fits a character level
+func memberLevel(referenceId uint32) error {
+	if referenceId > math.MaxUint8 {
+		return fmt.Errorf("unsupported level: %d", referenceId)
+	}
+	return nil
+}
+
+// memberRank checks that a referenceId identifies a guild rank, 1 (master) through 5
+func memberRank(referenceId uint32) error {
+	if referenceId > 5 {
+		return fmt.Errorf("unsupported guild rank: %d", referenceId)
+	}
+	return nil
+}
+
+func bound(v int) *int {
+	return &v
+}
+
+// conditionTypes declares the built-in condition types, in the order they are listed. Supporting a new condition type
+// only requires adding its definition and evaluator here, or registering it with RegisterConditionType; validation,
+// evaluation and data fetching follow from it.
+var conditionTypes = []ConditionTypeDefinition{
+	{conditionType: JobCondition, name: "job ID", source: CharactersSource, field: "jobId", evaluator: characterValue("Job ID", func(m character.Model) int { return int(m.JobId()) })},
+	{conditionType: MesoCondition, name: "meso", source: CharactersSource, field: "meso", evaluator: characterValue("Meso", func(m character.Model) int { return int(m.Meso()) })},
+	{conditionType: MapCondition, name: "map ID", source: CharactersSource, field: "mapId", evaluator: characterValue("Map ID", func(m character.Model) int { return int(m.MapId()) })},
+	{conditionType: FameCondition, name: "fame", source: CharactersSource, field: "fame", evaluator: characterValue("Fame", func(m character.Model) int { return int(m.Fame()) })},
+	{conditionType: ItemCondition, name: "item", reference: "item", source: InventorySource, field: "compartments.assets[templateId={referenceId}].quantity", evaluator: itemEvaluator},
+	{conditionType: GenderCondition, name: "gender", source: CharactersSource, field: "gender", evaluator: characterValue("Gender", func(m character.Model) int { return int(m.Gender()) })},
+	{conditionType: LevelCondition, name: "level", minValue: bound(0), source: CharactersSource, field: "level", evaluator: characterValue("Level", func(m character.Model) int { return int(m.Level()) })},
+	{conditionType: RebornsCondition, name: "reborns", minValue: bound(0), source: CharactersSource, field: "reborns", evaluator: characterValue("Reborns", func(m character.Model) int { return int(m.Reborns()) })},
+	{conditionType: DojoPointsCondition, name: "dojo points", minValue: bound(0), source: CharactersSource, field: "dojoPoints", evaluator: characterValue("Dojo Points", func(m character.Model) int { return int(m.DojoPoints()) })},
+	{conditionType: VanquisherKillsCondition, name: "vanquisher kills", minValue: bound(0), source: CharactersSource, field: "vanquisherKills", evaluator: characterValue("Vanquisher Kills", func(m character.Model) int { return int(m.VanquisherKills()) })},
+	{conditionType: GmLevelCondition, name: "GM level", minValue: bound(0), source: CharactersSource, field: "gm", evaluator: characterValue("GM Level", func(m character.Model) int { return m.GmLevel() })},
+	{conditionType: GuildIdCondition, name: "guild ID", minValue: bound(1), source: GuildsSource, field: "guild.id", evaluator: guildValue("Guild ID", func(m character.Model) int { return int(m.Guild().Id()) })},
+	{conditionType: GuildLeaderCondition, name: "guild leader", operators: []Operator{Equals, NotEquals}, minValue: bound(0), maxValue: bound(1), source: GuildsSource, field: "guild.leaderId", evaluator: guildLeaderEvaluator},
+	{conditionType: GuildRankCondition, name: "guild rank", minValue: bound(0), maxValue: bound(5), source: GuildsSource, field: "guild.members.rank", evaluator: guildValue("Guild Rank", func(m character.Model) int { return m.Guild().MemberRank(m.Id()) })},
+	{conditionType: GuildPointsCondition, name: "guild points", minValue: bound(0), source: GuildsSource, field: "guild.points", evaluator: guildValue("Guild Points", func(m character.Model) int { return int(m.Guild().Points()) })},
+	{conditionType: GuildCapacityCondition, name: "guild capacity", minValue: bound(0), source: GuildsSource, field: "guild.capacity", evaluator: guildValue("Guild Capacity", func(m character.Model) int { return int(m.Guild().Capacity()) })},
+	{conditionType: GuildMemberCountCondition, name: "guild member count", minValue: bound(0), source: GuildsSource, field: "guild.members", evaluator: guildValue("Guild Members", func(m character.Model) int { return len(m.Guild().Members()) })},
+	{conditionType: GuildOnlineMemberCountCondition, name: "guild online member count", minValue: bound(0), source: GuildsSource, field: "guild.members[online]", evaluator: guildValue("Guild Online Members", func(m character.Model) int { return m.Guild().OnlineMemberCount() })},
+	{conditionType: GuildMembersAtLevelCondition, name: "guild members at level", minValue: bound(0), reference: "member level", checkReference: memberLevel, source: GuildsSource, field: "guild.members[level>={referenceId}]", evaluator: guildMembers("Level", guild.Model.MembersAtLevel)},
+	{conditionType: GuildMembersAtRankCondition, name: "guild members at rank", minValue: bound(0), reference: "member rank", checkReference: memberRank, source: GuildsSource, field: "guild.members[rank<={referenceId}]", evaluator: guildMembers("Rank", guild.Model.MembersAtRank)},
+	{conditionType: QuestStatusCondition, name: "quest status", minValue: bound(0), maxValue: bound(3), reference: "quest", source: QuestsSource, field: "quests[{referenceId}].status", evaluator: questStatusEvaluator},
+	{conditionType: QuestProgressCondition, name: "quest progress", reference: "quest", step: "progress step", source: QuestsSource, field: "quests[{referenceId}].progress[{step}]", evaluator: questProgressEvaluator},
+	{conditionType: QuestCompletedCountCondition, name: "distinct completed quests", minValue: bound(0), source: QuestsSource, field: "quests[status=COMPLETED]", evaluator: questCompletedCountEvaluator},
+	{conditionType: QuestCompletedWithinCondition, name: "seconds since quest completion", minValue: bound(0), reference: "quest", source: QuestsSource, field: "quests[{referenceId}].completedAt", evaluator: questElapsed("completion", quest.Model.CompletedAt)},
+	{conditionType: QuestCompletedBeforeCondition, name: "quest completion date", operators: []Operator{LessThan, LessEqual}, minValue: bound(0), reference: "quest", source: QuestsSource, field: "quests[{referenceId}].completedAt", evaluator: questCompletedBeforeEvaluator},
+	{conditionType: QuestStartedWithinCondition, name: "seconds since quest start", minValue: bound(0), reference: "quest", source: QuestsSource, field: "quests[{referenceId}].startedAt", evaluator: questElapsed("start", quest.Model.StartedAt)},
+	{conditionType: QuestForfeitCountCondition, name: "quest forfeits", minValue: bound(0), reference: "quest", source: QuestsSource, field: "quests[{referenceId}].forfeitCount", evaluator: questForfeitCountEvaluator},
+	{conditionType: UnclaimedMarriageGiftsCondition, name: "marriage gift", operators: []Operator{Equals}, minValue: bound(0), maxValue: bound(1), source: MarriageSource, field: "hasUnclaimedGifts", evaluator: unclaimedMarriageGiftsEvaluator},
+	{conditionType: StrengthCondition, name: "strength", source: CharactersSource, field: "strength", evaluator: characterValue("Strength", func(m character.Model) int { return int(m.Strength()) })},
+	{conditionType: DexterityCondition, name: "dexterity", source: CharactersSource, field: "dexterity", evaluator: characterValue("Dexterity", func(m character.Model) int { return int(m.Dexterity()) })},
+	{conditionType: IntelligenceCondition, name: "intelligence", source: CharactersSource, field: "intelligence", evaluator: characterValue("Intelligence", func(m character.Model) int { return int(m.Intelligence()) })},
+	{conditionType: LuckCondition, name: "luck", source: CharactersSource, field: "luck", evaluator: characterValue("Luck", func(m character.Model) int { return int(m.Luck()) })},
+	{conditionType: EquippedCondition, name: "equipped item", minValue: bound(0), reference: "equipped item", step: "equipment slot type", optional: qualifiers{step: true}, checkStep: slotType, source: InventorySource, field: "equipment[templateId={referenceId}]", evaluator: equippedEvaluator},
+	{conditionType: ItemOwnedCondition, name: "owned item", minValue: bound(0), reference: "owned item", source: InventorySource, field: "compartments.assets[templateId={referenceId}].quantity+equipment[templateId={referenceId}]", evaluator: itemOwnedEvaluator},
+	{conditionType: ItemExpiringWithinCondition, name: "item expiration", minValue: bound(0), reference: "expiring item", source: InventorySource, field: "assets[templateId={referenceId}].expiration", evaluator: itemExpiringWithinEvaluator},
+	{conditionType: TotalStrengthCondition, name: "total strength", minValue: bound(0), source: InventorySource, field: "strength+equipment[].strength", evaluator: totalStatistic("Strength", equipment.Strength, func(m character.Model) int { return int(m.Strength()) })},
+	{conditionType: TotalDexterityCondition, name: "total dexterity", minValue: bound(0), source: InventorySource, field: "dexterity+equipment[].dexterity", evaluator: totalStatistic("Dexterity", equipment.Dexterity, func(m character.Model) int { return int(m.Dexterity()) })},
+	{conditionType: TotalIntelligenceCondition, name: "total intelligence", minValue: bound(0), source: InventorySource, field: "intelligence+equipment[].intelligence", evaluator: totalStatistic("Intelligence", equipment.Intelligence, func(m character.Model) int { return int(m.Intelligence()) })},
+	{conditionType: TotalLuckCondition, name: "total luck", minValue: bound(0), source: InventorySource, field: "luck+equipment[].luck", evaluator: totalStatistic("Luck", equipment.Luck, func(m character.Model) int { return int(m.Luck()) })},
+	{conditionType: TotalHpCondition, name: "total HP", minValue: bound(0), source: InventorySource, field: "maxHp+equipment[].hp", evaluator: totalStatistic("HP", equipment.HP, func(m character.Model) int { return int(m.MaxHp()) })},
+	{conditionType: TotalMpCondition, name: "total MP", minValue: bound(0), source: InventorySource, field: "maxMp+equipment[].mp", evaluator: totalStatistic("MP", equipment.MP, func(m character.Model) int { return int(m.MaxMp()) })},
+	{conditionType: TotalWeaponAttackCondition, name: "total weapon attack", minValue: bound(0), source: InventorySource, field: "equipment[].weaponAttack", evaluator: totalStatistic("Weapon Attack", equipment.WeaponAttack, nil)},
+	{conditionType: TotalMagicAttackCondition, name: "total magic attack", minValue: bound(0), source: InventorySource, field: "equipment[].magicAttack", evaluator: totalStatistic("Magic Attack", equipment.MagicAttack, nil)},
+	{conditionType: TotalWeaponDefenseCondition, name: "total weapon defense", minValue: bound(0), source: InventorySource, field: "equipment[].weaponDefense", evaluator: totalStatistic("Weapon Defense", equipment.WeaponDefense, nil)},
+	{conditionType: TotalMagicDefenseCondition, name: "total magic defense", minValue: bound(0), source: InventorySource, field: "equipment[].magicDefense", evaluator: totalStatistic("Magic Defense", equipment.MagicDefense, nil)},
+	{conditionType: TotalAccuracyCondition, name: "total accuracy", minValue: bound(0), source: InventorySource, field: "equipment[].accuracy", evaluator: totalStatistic("Accuracy", equipment.Accuracy, nil)},
+	{conditionType: TotalAvoidabilityCondition, name: "total avoidability", minValue: bound(0), source: InventorySource, field: "equipment[].avoidability", evaluator: totalStatistic("Avoidability", equipment.Avoidability, nil)},
+	{conditionType: TotalSpeedCondition, name: "total speed", minValue: bound(0), source: InventorySource, field: "equipment[].speed", evaluator: totalStatistic("Speed", equipment.Speed, nil)},
+	{conditionType: TotalJumpCondition, name: "total jump", minValue: bound(0), source: InventorySource, field: "equipment[].jump", evaluator: totalStatistic("Jump", equipment.Jump, nil)},
+	{conditionType: FreeSlotsCondition, name: "free slots", minValue: bound(0), reference: "inventory type", checkReference: inventoryType, source: InventorySource, field: "compartments[type={referenceId}].capacity-assets", evaluator: freeSlotsEvaluator},
+	{conditionType: JobBranchCondition, name: "job branch", operators: []Operator{Equals, NotEquals}, minValue: bound(0), maxValue: bound(1), reference: "job branch", checkReference: jobId, source: CharactersSource, field: "jobId", evaluator: jobBranchEvaluator},
+	{conditionType: JobAdvancementCondition, name: "job advancement", minValue: bound(0), maxValue: bound(4), source: CharactersSource, field: "jobId", evaluator: characterValue("Job Advancement", func(m character.Model) int { return int(m.JobAdvancement()) })},
+	{conditionType: JobFamilyCondition, name: "job family", minValue: bound(0), maxValue: bound(3), source: CharactersSource, field: "jobId", evaluator: characterValue("Job Family", func(m character.Model) int { return int(m.JobFamily()) })},
+	{conditionType: HpCondition, name: "HP", minValue: bound(0), source: CharactersSource, field: "hp", evaluator: characterValue("HP", func(m character.Model) int { return int(m.Hp()) })},
+	{conditionType: MaxHpCondition, name: "max HP", minValue: bound(0), source: CharactersSource, field: "maxHp", evaluator: characterValue("Max HP", func(m character.Model) int { return int(m.MaxHp()) })},
+	{conditionType: HpPercentCondition, name: "HP percent", minValue: bound(0), maxValue: bound(100), source: CharactersSource, field: "hp/maxHp", evaluator: characterValue("HP %", func(m character.Model) int { return m.HpPercent() })},
+	{conditionType: MpCondition, name: "MP", minValue: bound(0), source: CharactersSource, field: "mp", evaluator: characterValue("MP", func(m character.Model) int { return int(m.Mp()) })},
+	{conditionType: MaxMpCondition, name: "max MP", minValue: bound(0), source: CharactersSource, field: "maxMp", evaluator: characterValue("Max MP", func(m character.Model) int { return int(m.MaxMp()) })},
+	{conditionType: MpPercentCondition, name: "MP percent", minValue: bound(0), maxValue: bound(100), source: CharactersSource, field: "mp/maxMp", evaluator: characterValue("MP %", func(m character.Model) int { return m.MpPercent() })},
+	{conditionType: ApCondition, name: "AP", minValue: bound(0), source: CharactersSource, field: "ap", evaluator: characterValue("AP", func(m character.Model) int { return int(m.Ap()) })},
+	{conditionType: HpMpUsedCondition, name: "HP/MP AP used", minValue: bound(0), source: CharactersSource, field: "hpMpUsed", evaluator: characterValue("HP/MP AP Used", func(m character.Model) int { return m.HpMpUsed() })},
+	{conditionType: RemainingSpCondition, name: "remaining SP", minValue: bound(0), source: CharactersSource, field: "sp[skillBook]", evaluator: characterValue("Remaining SP", func(m character.Model) int { return int(m.RemainingSp()) })},
+	{conditionType: ExperienceCondition, name: "experience", minValue: bound(0), source: CharactersSource, field: "experience", evaluator: characterValue("Experience", func(m character.Model) int { return int(m.Experience()) })},
+	{conditionType: ExperiencePercentCondition, name: "experience percent", minValue: bound(0), maxValue: bound(100), source: CharactersSource, field: "experience/expTable[level]", evaluator: characterValue("Experience %", func(m character.Model) int { return m.ExperiencePercent() })},
+	{conditionType: GachaponExperienceCondition, name: "gachapon experience", minValue: bound(0), source: CharactersSource, field: "gachaponExperience", evaluator: characterValue("Gachapon Experience", func(m character.Model) int { return int(m.GachaponExperience()) })},
+	{conditionType: PetOwnedCondition, name: "owned pet", minValue: bound(0), reference: "pet", source: InventorySource, field: "compartments[type=5].assets[templateId={referenceId}]", evaluator: petOwnedEvaluator},
+	{conditionType: PetsSummonedCondition, name: "summoned pets", minValue: bound(0), maxValue: bound(3), source: InventorySource, field: "compartments[type=5].assets.referenceData.slot", evaluator: petsSummonedEvaluator},
+	{conditionType: PetLevelCondition, name: "pet level", minValue: bound(0), maxValue: bound(30), reference: "pet", source: InventorySource, field: "compartments[type=5].assets[templateId={referenceId}].referenceData.level", evaluator: petMaximum("level", func(p asset.PetReferenceData) int { return int(p.Level()) })},
+	{conditionType: PetClosenessCondition, name: "pet closeness", minValue: bound(0), reference: "pet", source: InventorySource, field: "compartments[type=5].assets[templateId={referenceId}].referenceData.closeness", evaluator: petMaximum("closeness", func(p asset.PetReferenceData) int { return int(p.Closeness()) })},
+	{conditionType: PetFullnessCondition, name: "pet fullness", minValue: bound(0), maxValue: bound(100), reference: "pet", optional: qualifiers{referenceId: true}, source: InventorySource, field: "compartments[type=5].assets.referenceData.fullness", evaluator: petFullnessEvaluator},
+	{conditionType: HairCondition, name: "hair", minValue: bound(0), source: CharactersSource, field: "hair", evaluator: characterValue("Hair", func(m character.Model) int { return int(m.Hair()) })},
+	{conditionType: HairStyleCondition, name: "hair style", minValue: bound(0), source: CharactersSource, field: "hair-hair%10", evaluator: characterValue("Hair Style", func(m character.Model) int { return int(m.HairStyle()) })},
+	{conditionType: HairColorCondition, name: "hair color", minValue: bound(0), maxValue: bound(9), source: CharactersSource, field: "hair%10", evaluator: characterValue("Hair Color", func(m character.Model) int { return int(m.HairColor()) })},
+	{conditionType: FaceCondition, name: "face", minValue: bound(0), source: CharactersSource, field: "face", evaluator: characterValue("Face", func(m character.Model) int { return int(m.Face()) })},
+	{conditionType: FaceStyleCondition, name: "face style", minValue: bound(0), source: CharactersSource, field: "face-face/100%10*100", evaluator: characterValue("Face Style", func(m character.Model) int { return int(m.FaceStyle()) })},
+	{conditionType: FaceColorCondition, name: "face color", minValue: bound(0), maxValue: bound(9), source: CharactersSource, field: "face/100%10", evaluator: characterValue("Face Color", func(m character.Model) int { return int(m.FaceColor()) })},
+	{conditionType: SkinColorCondition, name: "skin color", minValue: bound(0), source: CharactersSource, field: "skinColor", evaluator: characterValue("Skin Color", func(m character.Model) int { return int(m.SkinColor()) })},
+	{conditionType: MapRegionCondition, name: "map region", minValue: bound(0), source: CharactersSource, field: "mapId/1000000", evaluator: characterValue("Map Region", func(m character.Model) int { return int(m.MapRegion()) })},
+	{conditionType: MapContinentCondition, name: "map continent", minValue: bound(0), maxValue: bound(9), source: CharactersSource, field: "mapId/100000000", evaluator: characterValue("Map Continent", func(m character.Model) int { return int(m.MapContinent()) })},
+	{conditionType: MapInCondition, name: "map", operators: []Operator{In, NotIn}, minValue: bound(0), step: "map group", optional: qualifiers{step: true}, stepValues: "mapGroups[{step}]", source: CharactersSource, field: "mapId", evaluator: mapInEvaluator},
+	{conditionType: DateRangeCondition, name: "date", minValue: bound(0), source: ConfigurationSource, field: "timezone", evaluator: serverTimeValue("Date", dateOf)},
+	{conditionType: MonthDayCondition, name: "month and day", minValue: bound(101), maxValue: bound(1231), wraps: true, source: ConfigurationSource, field: "timezone", evaluator: serverTimeValue("Month and Day", monthDayOf)},
+	{conditionType: DayOfWeekCondition, name: "day of week", minValue: bound(0), maxValue: bound(6), wraps: true, source: ConfigurationSource, field: "timezone", evaluator: serverTimeValue("Day of Week", func(now time.Time) int { return int(now.Weekday()) })},
+	{conditionType: HourRangeCondition, name: "hour", minValue: bound(0), maxValue: bound(23), wraps: true, source: ConfigurationSource, field: "timezone", evaluator: serverTimeValue("Hour", time.Time.Hour)},
+}
+
+// registry holds the registered condition type definitions, indexed by type and in registration order
+var registry = struct {
+	sync.RWMutex
+	byType  map[ConditionType]ConditionTypeDefinition
+	ordered []ConditionTypeDefinition
+}{byType: make(map[ConditionType]ConditionTypeDefinition)}
+
+func init() {
+	for _, d := range conditionTypes {
+		if err := RegisterConditionType(d); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegisterConditionType adds a condition type to those supported. It fails when the definition is incomplete or its
+// type is already registered.
+func RegisterConditionType(d ConditionTypeDefinition) error {
+	if err := d.check(); err != nil {
+		return err
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+	if _, ok := registry.byType[d.conditionType]; ok {
+		return fmt.Errorf("condition type %s is already registered", d.conditionType)
+	}
+	registry.byType[d.conditionType] = d
+	registry.ordered = append(registry.ordered, d)
+	return nil
+}
+
+// check verifies that a definition carries everything conditions of the type are validated and evaluated with
+func (d ConditionTypeDefinition) check() error {
+	if d.conditionType == "" {
+		return fmt.Errorf("condition type is required")
+	}
+	if d.name == "" {
+		return fmt.Errorf("name is required for condition type %s", d.conditionType)
+	}
+	if !slices.Contains(dataSources, d.source) {
+		return fmt.Errorf("unsupported data source for condition type %s: %s", d.conditionType, d.source)
+	}
+	if d.evaluator == nil {
+		return fmt.Errorf("evaluator is required for condition type %s", d.conditionType)
+	}
+	if !slices.Contains(d.evaluator.Needs(), d.source) {
+		return fmt.Errorf("data source %s of condition type %s is not read by its evaluator", d.source, d.conditionType)
+	}
+	return nil
+}
+
+// ConditionTypes returns the definition of every supported condition type, in registration order
+func ConditionTypes() []ConditionTypeDefinition {
+	registry.RLock()
+	defer registry.RUnlock()
+	return slices.Clone(registry.ordered)
+}
+
+// LookupConditionType returns the definition of a supported condition type
+func LookupConditionType(conditionType string) (ConditionTypeDefinition, error) {
+	registry.RLock()
+	d, ok := registry.byType[ConditionType(conditionType)]
+	registry.RUnlock()
+	if !ok {
+		return ConditionTypeDefinition{}, fmt.Errorf("unsupported condition type: %s", conditionType)
+	}
+	return d, nil
+}
+
+// ConditionTypeDefinitionBuilder is used to safely construct ConditionTypeDefinition objects
+type ConditionTypeDefinitionBuilder struct {
+	definition ConditionTypeDefinition
+}
+
+// NewConditionTypeDefinitionBuilder creates a builder for a condition type with a human readable name, read from a
+// field of a data source by an evaluator
+func NewConditionTypeDefinitionBuilder(conditionType ConditionType, name string, source DataSource, field string, evaluator Evaluator) *ConditionTypeDefinitionBuilder {
+	return &ConditionTypeDefinitionBuilder{definition: ConditionTypeDefinition{
+		conditionType: conditionType,
+		name:          name,
+		source:        source,
+		field:         field,
+		evaluator:     evaluator,
+	}}
+}
+
+// SetOperators restricts the operators supported by the condition type
+func (b *ConditionTypeDefinitionBuilder) SetOperators(operators ...Operator) *ConditionTypeDefinitionBuilder {
+	b.definition.operators = operators
+	return b
+}
+
+// SetMinValue sets the inclusive lower bound of the expected values
+func (b *ConditionTypeDefinitionBuilder) SetMinValue(minValue int) *ConditionTypeDefinitionBuilder {
+	b.definition.minValue = bound(minValue)
+	return b
+}
+
+// SetMaxValue sets the inclusive upper bound of the expected values
+func (b *ConditionTypeDefinitionBuilder) SetMaxValue(maxValue int) *ConditionTypeDefinitionBuilder {
+	b.definition.maxValue = bound(maxValue)
+	return b
+}
+
+// SetReference requires a referenceId identifying the named entity, checked by check when not nil
+func (b *ConditionTypeDefinitionBuilder) SetReference(reference string, check func(referenceId uint32) error) *ConditionTypeDefinitionBuilder {
+	b.definition.reference = reference
+	b.definition.checkReference = check
+	return b
+}
+
+// SetStep requires a step identifying the named entity, checked by check when not nil
+func (b *ConditionTypeDefinitionBuilder) SetStep(step string, check func(step string) error) *ConditionTypeDefinitionBuilder {
+	b.definition.step = step
+	b.definition.checkStep = check
+	return b
+}
+
+// SetOptionalReferenceId lets conditions of the type omit the referenceId
+func (b *ConditionTypeDefinitionBuilder) SetOptionalReferenceId() *ConditionTypeDefinitionBuilder {
+	b.definition.optional.referenceId = true
+	return b
+}
+
+// SetOptionalStep lets conditions of the type omit the step
+func (b *ConditionTypeDefinitionBuilder) SetOptionalStep() *ConditionTypeDefinitionBuilder {
+	b.definition.optional.step = true
+	return b
+}
+
+// SetStepValues lets the step name a tenant configured list of values, given in place of values, held in the field of
+// the tenant configuration
+func (b *ConditionTypeDefinitionBuilder) SetStepValues(field string) *ConditionTypeDefinitionBuilder {
+	b.definition.stepValues = field
+	return b
+}
+
+// SetWraps lets between ranges wrap past the largest value to the smallest
+func (b *ConditionTypeDefinitionBuilder) SetWraps() *ConditionTypeDefinitionBuilder {
+	b.definition.wraps = true
+	return b
+}
+
+// Build validates and returns the condition type definition
+func (b *ConditionTypeDefinitionBuilder) Build() (ConditionTypeDefinition, error) {
+	if err := b.definition.check(); err != nil {
+		return ConditionTypeDefinition{}, err
+	}
+	return b.definition, nil
+}
@@ -0,0 +1,105 @@
+package validation_test
+
+import (
+	"atlas-query-aggregator/character"
+	"atlas-query-aggregator/validation"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+var (
+	registerStat sync.Once
+	seen         validation.Condition // Condition last given to the stat evaluator
+)
+
+// statEvaluator reads the strength or, for a dex step, the dexterity of the character, recording the condition it was given
+func statEvaluator() validation.Evaluator {
+	return validation.NewEvaluator(func(c validation.Condition, ctx validation.ValidationContext) validation.Evaluation {
+		seen = c
+		actualValue := int(ctx.Character().Strength())
+		if c.Step() == "dex" {
+			actualValue = int(ctx.Character().Dexterity())
+		}
+		return validation.Evaluation{ActualValue: actualValue, Description: fmt.Sprintf("Stat %s %s", c.Step(), c.Expectation())}
+	}, validation.CharactersSource)
+}
+
+// TestRegisterConditionType_External tests that a condition type registered outside the package can read the condition
+// it evaluates through its accessors
+func TestRegisterConditionType_External(t *testing.T) {
+	registerStat.Do(func() {
+		d, err := validation.NewConditionTypeDefinitionBuilder("testStat", "stat", validation.CharactersSource, "{step}", statEvaluator()).
+			SetOperators(validation.GreaterEqual, validation.In, validation.Between).
+			SetMinValue(0).
+			SetStep("stat", nil).
+			Build()
+		if err != nil {
+			t.Fatalf("Build() unexpected error: %v", err)
+		}
+		if err := validation.RegisterConditionType(d); err != nil {
+			t.Fatalf("RegisterConditionType() unexpected error: %v", err)
+		}
+	})
+
+	min, max := 10, 20
+	tests := []struct {
+		name            string
+		input           validation.ConditionInput
+		wantPassed      bool
+		wantDescription string
+		wantOperator    validation.Operator
+		wantValue       int
+		wantValues      []int
+		wantMin         int
+		wantMax         int
+	}{
+		{
+			name:            "Single value",
+			input:           validation.ConditionInput{Type: "testStat", Operator: ">=", Value: 12, Step: "str"},
+			wantPassed:      true,
+			wantDescription: "Stat str >= 12",
+			wantOperator:    validation.GreaterEqual,
+			wantValue:       12,
+		},
+		{
+			name:            "Set of values",
+			input:           validation.ConditionInput{Type: "testStat", Operator: "in", Values: []int{4, 5}, Step: "dex"},
+			wantPassed:      false,
+			wantDescription: "Stat dex in [4, 5]",
+			wantOperator:    validation.In,
+			wantValues:      []int{4, 5},
+		},
+		{
+			name:            "Range",
+			input:           validation.ConditionInput{Type: "testStat", Operator: "between", Min: &min, Max: &max, Step: "dex"},
+			wantPassed:      true,
+			wantDescription: "Stat dex between 10 and 20",
+			wantOperator:    validation.Between,
+			wantMin:         10,
+			wantMax:         20,
+		},
+	}
+
+	ctx := validation.NewValidationContext(character.NewModelBuilder().SetStrength(15).SetDexterity(18).Build())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, err := validation.NewConditionBuilder().FromInput(tt.input).Build()
+			if err != nil {
+				t.Fatalf("Build() unexpected error: %v", err)
+			}
+
+			result := condition.EvaluateWithContext(ctx)
+			if result.Passed != tt.wantPassed || result.Description != tt.wantDescription {
+				t.Errorf("Result = %+v, want passed %v with description %q", result, tt.wantPassed, tt.wantDescription)
+			}
+			if seen.Step() != tt.input.Step || seen.ReferenceId() != 0 || seen.Operator() != tt.wantOperator {
+				t.Errorf("Evaluator saw step %q, referenceId %d, operator %s", seen.Step(), seen.ReferenceId(), seen.Operator())
+			}
+			if seen.Value() != tt.wantValue || !reflect.DeepEqual(seen.Values(), tt.wantValues) || seen.Min() != tt.wantMin || seen.Max() != tt.wantMax {
+				t.Errorf("Evaluator saw value %d, values %v, min %d, max %d", seen.Value(), seen.Values(), seen.Min(), seen.Max())
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package validation
+
+import (
+	"atlas-query-aggregator/character"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// minimalInput returns a valid input for the condition type using its first operator and smallest allowed value
+func minimalInput(d ConditionTypeDefinition) ConditionInput {
+	input := ConditionInput{Type: string(d.Type()), Operator: string(d.Operators()[0])}
+	if d.MinValue() != nil {
+		input.Value = *d.MinValue()
+	}
+	if Operator(input.Operator).IsSet() {
+		input.Values = []int{input.Value}
+	}
+	if d.RequiresReferenceId() {
+		input.ReferenceId = 2000001
+		if d.validateQualifiers(input.ReferenceId, "") != nil {
+			// Reference IDs enumerating a small set, e.g. inventory types
+			input.ReferenceId = 1
+		}
+	}
+	if d.RequiresStep() {
+		input.Step = "mobsKilled"
+	}
+	return input
+}
+
+// TestRegistry_Consistency tests that the builder and the REST validation accept every registered condition type
+func TestRegistry_Consistency(t *testing.T) {
+	for _, d := range ConditionTypes() {
+		t.Run(string(d.Type()), func(t *testing.T) {
+			input := minimalInput(d)
+			if err := validateConditionInput(input); err != nil {
+				t.Errorf("validateConditionInput() rejected %+v: %v", input, err)
+			}
+			condition, err := NewConditionBuilder().FromInput(input).Build()
+			if err != nil {
+				t.Fatalf("FromInput() rejected %+v: %v", input, err)
+			}
+			if source, _ := condition.source(); source != d.Source() {
+				t.Errorf("source() = %s, want %s", source, d.Source())
+			}
+		})
+	}
+
+	if _, err := LookupConditionType("unknown"); err == nil || !strings.Contains(err.Error(), "unsupported condition type: unknown") {
+		t.Errorf("Expected unsupported condition type error, got %v", err)
+	}
+}
+
+// TestRegistry_Validation tests the operator, value range and required field checks of the registry
+func TestRegistry_Validation(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         ConditionInput
+		errorContains string
+	}{
+		{
+			name:  "Guild leader is accepted",
+			input: ConditionInput{Type: "guildLeader", Operator: "=", Value: 1},
+		},
+		{
+			name:          "Guild leader out of range",
+			input:         ConditionInput{Type: "guildLeader", Operator: "=", Value: 2},
+			errorContains: "guild leader value must be between 0 and 1",
+		},
+		{
+			name:          "Unsupported operator for type",
+			input:         ConditionInput{Type: "hasUnclaimedMarriageGifts", Operator: ">=", Value: 1},
+			errorContains: "operator >= is not supported for marriage gift conditions",
+		},
+		{
+			name:          "Negative level",
+			input:         ConditionInput{Type: "level", Operator: ">=", Value: -1},
+			errorContains: "level value must be non-negative",
+		},
+		{
+			name:          "Guild ID below minimum",
+			input:         ConditionInput{Type: "guildId", Operator: "in", Values: []int{5, 0}},
+			errorContains: "guild ID value must be at least 1",
+		},
+		{
+			name:          "Quest status range applies to between",
+			input:         ConditionInput{Type: "questStatus", Operator: "between", Min: intPtr(1), Max: intPtr(4), ReferenceId: 1001},
+			errorContains: "quest status value must be between 0 and 3",
+		},
+		{
+			name:          "Missing reference",
+			input:         ConditionInput{Type: "item", Operator: ">=", Value: 1},
+			errorContains: "referenceId is required for item conditions",
+		},
+		{
+			name:          "Missing step",
+			input:         ConditionInput{Type: "questProgress", Operator: ">=", Value: 1, ReferenceId: 1001},
+			errorContains: "step is required for quest progress conditions",
+		},
+		{
+			name:          "Unknown inventory type",
+			input:         ConditionInput{Type: "freeSlots", Operator: ">=", Value: 1, ReferenceId: 6},
+			errorContains: "unsupported inventory type: 6",
+		},
+		{
+			name:          "Unknown equipment slot type",
+			input:         ConditionInput{Type: "equipped", Operator: ">=", Value: 1, ReferenceId: 1002140, Step: "tail"},
+			errorContains: "unsupported equipment slot type: tail",
+		},
+		{
+			name:          "Deprecated item ID is checked as the reference",
+			input:         ConditionInput{Type: "jobBranch", Operator: "=", Value: 1, ItemId: 70000},
+			errorContains: "unsupported job: 70000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, buildErr := NewConditionBuilder().FromInput(tt.input).Build()
+			validateErr := validateConditionInput(tt.input)
+			for _, err := range []error{buildErr, validateErr} {
+				if tt.errorContains == "" {
+					if err != nil {
+						t.Errorf("Unexpected error: %v", err)
+					}
+					continue
+				}
+				if err == nil || !strings.Contains(err.Error(), tt.errorContains) {
+					t.Errorf("Expected error containing '%s', got '%v'", tt.errorContains, err)
+				}
+			}
+		})
+	}
+}
+
+// TestValidateConditionInput_ItemAndReference tests that the deprecated item ID is not combined with a reference ID
+func TestValidateConditionInput_ItemAndReference(t *testing.T) {
+	input := ConditionInput{Type: "jobBranch", Operator: "=", Value: 1, ReferenceId: 100, ItemId: 65500}
+	if err := validateConditionInput(input); err == nil || !strings.Contains(err.Error(), "both itemId and referenceId specified") {
+		t.Errorf("Expected both itemId and referenceId to be rejected, got %v", err)
+	}
+}
+
+// TestTransformConditionType tests the REST representation of a condition type
+func TestTransformConditionType(t *testing.T) {
+	d, err := LookupConditionType("questProgress")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	rm, err := TransformConditionType(d)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rm.GetID() != "questProgress" || !rm.RequiresReferenceId || !rm.RequiresStep || rm.DataSource != QuestsSource {
+		t.Errorf("Unexpected REST model %+v", rm)
+	}
+	if len(rm.Operators) != len(operators) {
+		t.Errorf("Expected every operator to be supported, got %v", rm.Operators)
+	}
+}
+
+// TestTransformConditionType_Dependencies tests that every data source read by a condition type and its optional
+// qualifiers are published
+func TestTransformConditionType_Dependencies(t *testing.T) {
+	tests := []struct {
+		conditionType    string
+		wantSources      []DataSource
+		wantOptionalRef  bool
+		wantOptionalStep bool
+		wantRequiresRef  bool
+		wantRequiresStep bool
+	}{
+		{conditionType: "level", wantSources: []DataSource{CharactersSource}},
+		{conditionType: "totalStrength", wantSources: []DataSource{CharactersSource, InventorySource}},
+		{conditionType: "equipped", wantSources: []DataSource{CharactersSource, InventorySource}, wantRequiresRef: true, wantOptionalStep: true},
+		{conditionType: "petFullness", wantSources: []DataSource{CharactersSource, InventorySource}, wantOptionalRef: true},
+		{conditionType: "questCompletedBefore", wantSources: []DataSource{QuestsSource, ConfigurationSource}, wantRequiresRef: true},
+		{conditionType: "dateRange", wantSources: []DataSource{ConfigurationSource}},
+		{conditionType: "mapIn", wantSources: []DataSource{CharactersSource, ConfigurationSource}, wantOptionalStep: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.conditionType, func(t *testing.T) {
+			d, err := LookupConditionType(tt.conditionType)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			rm, err := TransformConditionType(d)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(rm.DataSources, tt.wantSources) {
+				t.Errorf("DataSources = %v, want %v", rm.DataSources, tt.wantSources)
+			}
+			if rm.RequiresReferenceId != tt.wantRequiresRef || rm.OptionalReferenceId != tt.wantOptionalRef {
+				t.Errorf("RequiresReferenceId = %v, OptionalReferenceId = %v, want %v, %v", rm.RequiresReferenceId, rm.OptionalReferenceId, tt.wantRequiresRef, tt.wantOptionalRef)
+			}
+			if rm.RequiresStep != tt.wantRequiresStep || rm.OptionalStep != tt.wantOptionalStep {
+				t.Errorf("RequiresStep = %v, OptionalStep = %v, want %v, %v", rm.RequiresStep, rm.OptionalStep, tt.wantRequiresStep, tt.wantOptionalStep)
+			}
+		})
+	}
+}
+
+// TestRegisterConditionType tests that registered condition types are validated and evaluated like built-in ones, and
+// that duplicate and incomplete definitions are rejected
+func TestRegisterConditionType(t *testing.T) {
+	evaluator := NewEvaluator(func(c Condition, ctx ValidationContext) Evaluation {
+		return Evaluation{ActualValue: int(ctx.Character().Ap()), Description: fmt.Sprintf("Unspent AP %s %d", c.operator, c.value)}
+	}, CharactersSource)
+	d, err := NewConditionTypeDefinitionBuilder("testUnspentAp", "unspent AP", CharactersSource, "ap", evaluator).
+		SetOperators(GreaterEqual, LessThan).
+		SetMinValue(0).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() unexpected error: %v", err)
+	}
+	if err := RegisterConditionType(d); err != nil {
+		t.Fatalf("RegisterConditionType() unexpected error: %v", err)
+	}
+	t.Cleanup(func() {
+		registry.Lock()
+		defer registry.Unlock()
+		delete(registry.byType, d.Type())
+		registry.ordered = registry.ordered[:len(registry.ordered)-1]
+	})
+
+	if err := RegisterConditionType(d); err == nil || !strings.Contains(err.Error(), "condition type testUnspentAp is already registered") {
+		t.Errorf("Expected duplicate registration error, got %v", err)
+	}
+	level, _ := LookupConditionType("level")
+	if err := RegisterConditionType(level); err == nil || !strings.Contains(err.Error(), "condition type level is already registered") {
+		t.Errorf("Expected duplicate registration error, got %v", err)
+	}
+	if _, err := NewConditionTypeDefinitionBuilder("testIncomplete", "incomplete", CharactersSource, "ap", nil).Build(); err == nil || !strings.Contains(err.Error(), "evaluator is required") {
+		t.Errorf("Expected missing evaluator error, got %v", err)
+	}
+	if _, err := NewConditionTypeDefinitionBuilder("testMisreported", "misreported", QuestsSource, "ap", evaluator).Build(); err == nil || !strings.Contains(err.Error(), "data source QUESTS of condition type testMisreported is not read by its evaluator") {
+		t.Errorf("Expected unread data source error, got %v", err)
+	}
+	if err := RegisterConditionType(ConditionTypeDefinition{}); err == nil || !strings.Contains(err.Error(), "condition type is required") {
+		t.Errorf("Expected missing condition type error, got %v", err)
+	}
+
+	types := ConditionTypes()
+	if types[len(types)-1].Type() != "testUnspentAp" {
+		t.Errorf("Expected registered condition type last, got %s", types[len(types)-1].Type())
+	}
+	if _, err := NewConditionBuilder().FromExpression("testUnspentAp=5").Build(); err == nil {
+		t.Error("Expected unsupported operator error")
+	}
+
+	condition, err := NewConditionBuilder().FromExpression("testUnspentAp>=5").Build()
+	if err != nil {
+		t.Fatalf("Build() unexpected error: %v", err)
+	}
+	result := condition.EvaluateWithContext(NewValidationContext(character.NewModelBuilder().SetAp(7).Build()))
+	if !result.Passed || result.ActualValue != 7 {
+		t.Errorf("Expected condition to pass with actual value 7, got %+v", result)
+	}
+}
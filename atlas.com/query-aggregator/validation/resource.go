@@ -2,49 +2,234 @@ package validation
 
 import (
 	"atlas-query-aggregator/rest"
+	"errors"
+	"fmt"
+	"github.com/Chronicle20/atlas-constants/world"
 	"github.com/Chronicle20/atlas-model/model"
 	"github.com/Chronicle20/atlas-rest/server"
 	"github.com/gorilla/mux"
 	"github.com/jtumidanski/api2go/jsonapi"
 	"github.com/sirupsen/logrus"
 	"net/http"
+	"strconv"
 )
 
 // InitResource registers the routes with the router
-func InitResource(si jsonapi.ServerInformation) server.RouteInitializer {
-	return func(r *mux.Router, l logrus.FieldLogger) {
-		r.HandleFunc("/validations", rest.RegisterInputHandler[RestModel](l)(si)("handle_validations", validationHandler)).Methods(http.MethodPost)
+func InitResource(si jsonapi.ServerInformation) func(csrp ConditionSetResolverProvider, crp ConfigurationResolverProvider) server.RouteInitializer {
+	return func(csrp ConditionSetResolverProvider, crp ConfigurationResolverProvider) server.RouteInitializer {
+		return func(r *mux.Router, l logrus.FieldLogger) {
+			r.HandleFunc("/validations", rest.RegisterInputHandler[RestModel](l)(si)("handle_validations", validationHandler(csrp, crp))).Methods(http.MethodPost)
+			r.HandleFunc("/accounts/{accountId}/worlds/{worldId}/validations", rest.RegisterInputHandler[AccountRestModel](l)(si)("handle_account_validations", accountValidationHandler(csrp, crp))).Methods(http.MethodPost)
+			r.HandleFunc("/validations/condition-types", rest.RegisterHandler(l)(si)("get_condition_types", handleGetConditionTypes)).Methods(http.MethodGet)
+			r.HandleFunc("/validations/compile", rest.RegisterInputHandler[CompileRestModel](l)(si)("handle_compile_validations", compileHandler)).Methods(http.MethodPost)
+		}
 	}
 }
 
-func validationHandler(d *rest.HandlerDependency, c *rest.HandlerContext, im RestModel) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		// Extract parameters from the REST model
-		characterId, conditions, err := Extract(im)
-		if err != nil {
-			d.Logger().WithError(err).Errorln("Failed to extract validation parameters")
-			w.WriteHeader(http.StatusBadRequest)
-			return
+func validationHandler(csrp ConditionSetResolverProvider, crp ConfigurationResolverProvider) rest.InputHandler[RestModel] {
+	return func(d *rest.HandlerDependency, c *rest.HandlerContext, im RestModel) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			// Replace a condition set reference with the stored conditions
+			im, err := ResolveConditionSet(csrp(d.Logger(), d.Context()))(im)
+			if errors.Is(err, ErrConditionSetNotFound) {
+				d.Logger().WithError(err).Errorln("Referenced condition set does not exist")
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			if err != nil {
+				d.Logger().WithError(err).Errorln("Failed to resolve condition set")
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			// Extract parameters from the REST model
+			characterId, conditions, err := Extract(im)
+			if err != nil {
+				d.Logger().WithError(err).Errorln("Failed to extract validation parameters")
+				writeInvalidConditions(d.Logger(), w, err)
+				return
+			}
+
+			// The mode decides how the condition results combine into the outcome
+			mode, err := ExtractMode(im)
+			if err != nil {
+				d.Logger().WithError(err).Errorln("Failed to extract validation mode")
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			decorators := []model.Decorator[ValidationResult]{ModeDecorator(mode)}
+
+			// Explain mode reports where each actual value came from
+			if v := r.URL.Query().Get("explain"); v != "" {
+				explain, err := strconv.ParseBool(v)
+				if err != nil {
+					d.Logger().WithError(err).Errorln("Unable to properly parse explain from query")
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				if explain {
+					decorators = append(decorators, Explain)
+				}
+			}
+
+			// Validate the conditions, loading only the data sources they need, including quests and marriage data
+			result, err := NewProcessor(d.Logger(), d.Context(), crp(d.Logger(), d.Context())).ValidateStructured(decorators...)(characterId, conditions)
+			if err != nil {
+				d.Logger().WithError(err).Errorln("Failed to validate conditions")
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			rms, err := model.Map(Transform)(model.FixedProvider(result))()
+			if err != nil {
+				d.Logger().WithError(err).Error("Failed to transform validation result")
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			rms.ConditionSetId = im.ConditionSetId
+			rms.ConditionSetVersion = im.ConditionSetVersion
+
+			// Marshal response
+			query := r.URL.Query()
+			queryParams := jsonapi.ParseQueryFields(&query)
+			server.MarshalResponse[RestModel](d.Logger())(w)(c.ServerInformation())(queryParams)(rms)
 		}
+	}
+}
+
+// accountValidationHandler validates conditions against every character the account identified by the path has in the
+// world identified by the path
+func accountValidationHandler(csrp ConditionSetResolverProvider, crp ConfigurationResolverProvider) rest.InputHandler[AccountRestModel] {
+	return func(d *rest.HandlerDependency, c *rest.HandlerContext, im AccountRestModel) http.HandlerFunc {
+		return rest.ParseAccountId(d.Logger(), func(accountId uint32) http.HandlerFunc {
+			return rest.ParseWorldId(d.Logger(), func(worldId world.Id) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					im.Id = accountId
+
+					// Replace a condition set reference with the stored conditions
+					vm, err := ResolveConditionSet(csrp(d.Logger(), d.Context()))(im.validation())
+					if errors.Is(err, ErrConditionSetNotFound) {
+						d.Logger().WithError(err).Errorln("Referenced condition set does not exist")
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+					if err != nil {
+						d.Logger().WithError(err).Errorln("Failed to resolve condition set")
+						w.WriteHeader(http.StatusBadRequest)
+						return
+					}
+
+					// Extract parameters from the REST model
+					_, conditions, err := Extract(vm)
+					if err != nil {
+						d.Logger().WithError(err).Errorln("Failed to extract validation parameters")
+						writeInvalidConditions(d.Logger(), w, err)
+						return
+					}
+
+					// The mode decides the outcome of each character, and the scope the outcome of the account
+					mode, err := ExtractMode(vm)
+					if err != nil {
+						d.Logger().WithError(err).Errorln("Failed to extract validation mode")
+						w.WriteHeader(http.StatusBadRequest)
+						return
+					}
+					scope, err := ParseAccountScope(im.Scope)
+					if err != nil {
+						d.Logger().WithError(err).Errorln("Failed to extract account scope")
+						w.WriteHeader(http.StatusBadRequest)
+						return
+					}
+					decorators := []model.Decorator[ValidationResult]{ModeDecorator(mode)}
+
+					// Explain mode reports where each actual value came from
+					if v := r.URL.Query().Get("explain"); v != "" {
+						explain, err := strconv.ParseBool(v)
+						if err != nil {
+							d.Logger().WithError(err).Errorln("Unable to properly parse explain from query")
+							w.WriteHeader(http.StatusBadRequest)
+							return
+						}
+						if explain {
+							decorators = append(decorators, Explain)
+						}
+					}
+
+					result, err := NewProcessor(d.Logger(), d.Context(), crp(d.Logger(), d.Context())).ValidateAccount(decorators...)(accountId, worldId, scope, conditions)
+					if err != nil {
+						d.Logger().WithError(err).Errorln("Failed to validate account conditions")
+						w.WriteHeader(http.StatusBadRequest)
+						return
+					}
 
-		// Validate the conditions using the structured validation
-		result, err := NewProcessor(d.Logger(), d.Context()).ValidateStructured()(characterId, conditions)
+					rm, err := model.Map(TransformAccount)(model.FixedProvider(result))()
+					if err != nil {
+						d.Logger().WithError(err).Error("Failed to transform account validation result")
+						w.WriteHeader(http.StatusInternalServerError)
+						return
+					}
+					rm.ConditionSetId = vm.ConditionSetId
+					rm.ConditionSetVersion = vm.ConditionSetVersion
+
+					query := r.URL.Query()
+					queryParams := jsonapi.ParseQueryFields(&query)
+					server.MarshalResponse[AccountRestModel](d.Logger())(w)(c.ServerInformation())(queryParams)(rm)
+				}
+			})
+		})
+	}
+}
+
+// writeInvalidConditions rejects a request whose conditions could not be extracted with a JSON:API error naming the
+// offending condition and, for placeholders, the parameter and the type it requires
+func writeInvalidConditions(l logrus.FieldLogger, w http.ResponseWriter, err error) {
+	e := rest.ErrorRestModel{Title: "Invalid conditions", Detail: err.Error(), Meta: map[string]interface{}{}}
+
+	var conditionErr ConditionError
+	if errors.As(err, &conditionErr) {
+		e.Source = &rest.ErrorSource{Pointer: fmt.Sprintf("/data/attributes/conditions/%d", conditionErr.Index)}
+		e.Meta["index"] = conditionErr.Index
+	}
+	var parameterErr ParameterError
+	if errors.As(err, &parameterErr) {
+		e.Title = "Invalid parameter"
+		e.Meta["parameter"] = parameterErr.Parameter
+		e.Meta["expected"] = parameterErr.Expected
+	}
+	rest.WriteErrors(l, w, http.StatusBadRequest, e)
+}
+
+// compileHandler checks conditions without fetching character data. Problems with the conditions are reported in the
+// response body rather than as an error status.
+func compileHandler(d *rest.HandlerDependency, c *rest.HandlerContext, im CompileRestModel) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		compilation := Compile(im.Parameters, im.Mode, im.Threshold, im.Conditions)
+
+		rm, err := model.Map(TransformCompilation)(model.FixedProvider(compilation))()
 		if err != nil {
-			d.Logger().WithError(err).Errorln("Failed to validate conditions")
-			w.WriteHeader(http.StatusBadRequest)
+			d.Logger().WithError(err).Error("Failed to transform compilation")
+			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
 
-		rms, err := model.Map(Transform)(model.FixedProvider(result))()
+		query := r.URL.Query()
+		queryParams := jsonapi.ParseQueryFields(&query)
+		server.MarshalResponse[CompileRestModel](d.Logger())(w)(c.ServerInformation())(queryParams)(rm)
+	}
+}
+
+// handleGetConditionTypes lists every supported condition type so tools can build condition editors
+func handleGetConditionTypes(d *rest.HandlerDependency, c *rest.HandlerContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := model.SliceMap(TransformConditionType)(model.FixedProvider(ConditionTypes()))()()
 		if err != nil {
-			d.Logger().WithError(err).Error("Failed to transform validation result")
+			d.Logger().WithError(err).Error("Failed to transform condition types")
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
 
-		// Marshal response
 		query := r.URL.Query()
 		queryParams := jsonapi.ParseQueryFields(&query)
-		server.MarshalResponse[RestModel](d.Logger())(w)(c.ServerInformation())(queryParams)(rms)
+		server.MarshalResponse[[]ConditionTypeRestModel](d.Logger())(w)(c.ServerInformation())(queryParams)(res)
 	}
 }
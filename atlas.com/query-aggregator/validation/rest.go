@@ -2,12 +2,16 @@ package validation
 
 import (
 	"fmt"
+	"github.com/google/uuid"
 	"github.com/jtumidanski/api2go/jsonapi"
 	"strconv"
 )
 
 const (
-	Resource = "validations"
+	Resource              = "validations"
+	AccountResource       = "account-validations"
+	CompileResource       = "compilations"
+	ConditionTypeResource = "condition-types"
 )
 
 // RestModel represents the REST model for validation requests and responses
@@ -100,11 +104,92 @@ const (
 //       }
 //     ]
 //   }
+//
+// Example request combining conditions with nested groups
+// (level >= 30 and either job 100 or not holding item 4001000):
+//   {
+//     "conditions": [
+//       {
+//         "all": [
+//           { "type": "level", "operator": ">=", "value": 30 },
+//           {
+//             "any": [
+//               { "type": "jobId", "operator": "=", "value": 100 },
+//               { "not": { "type": "item", "operator": ">=", "value": 1, "referenceId": 4001000 } }
+//             ]
+//           }
+//         ]
+//       }
+//     ]
+//   }
+//
+// The same request written as a compact expression:
+//   {
+//     "conditions": [
+//       { "expression": "level>=30 && (jobId=100 || !item[4001000]>=1)" }
+//     ]
+//   }
+//
+// Example request using set membership and range operators:
+//   {
+//     "conditions": [
+//       { "type": "jobId", "operator": "in", "values": [100, 110, 120, 130] },
+//       { "type": "mapId", "operator": "!=", "value": 910000000 },
+//       { "type": "level", "operator": "between", "min": 30, "max": 70 }
+//     ]
+//   }
+//
+// Example request evaluating a stored condition set pinned to version 2
+// (omit conditionSetVersion to use the latest version):
+//   {
+//     "conditionSetId": "5d1c1a7e-0f55-4c6c-9a37-6f0b8c0f2d11",
+//     "conditionSetVersion": 2
+//   }
+//
+// Example request binding placeholders to request parameters
+// (stored condition sets may use placeholders in the same way):
+//   {
+//     "conditions": [
+//       { "type": "item", "operator": ">=", "valueParam": "count", "referenceIdParam": "itemId" },
+//       { "expression": "level>=$minLevel" }
+//     ],
+//     "parameters": { "count": 10, "itemId": 2000001, "minLevel": 30 }
+//   }
+//
+// Example request passing when any 2 of 3 conditions pass:
+//   {
+//     "mode": "atLeast:2",
+//     "conditions": [
+//       { "type": "level", "operator": ">=", "value": 70 },
+//       { "type": "fame", "operator": ">=", "value": 100 },
+//       { "type": "dojoPoints", "operator": ">=", "value": 5000 }
+//     ]
+//   }
+//
+// Example request passing when the weights of the passing conditions sum to at least 5
+// (conditions without a weight contribute 1):
+//   {
+//     "mode": "score",
+//     "threshold": 5,
+//     "conditions": [
+//       { "type": "level", "operator": ">=", "value": 120, "weight": 3 },
+//       { "type": "reborns", "operator": ">=", "value": 1, "weight": 2 },
+//       { "type": "fame", "operator": ">=", "value": 50 }
+//     ]
+//   }
 type RestModel struct {
-	Id         uint32            `json:"-"`
-	Conditions []ConditionInput  `json:"conditions,omitempty"`
-	Passed     bool              `json:"passed"`
-	Results    []ConditionResult `json:"results,omitempty"`
+	Id                  uint32            `json:"-"`
+	Conditions          []ConditionInput  `json:"conditions,omitempty"`
+	ConditionSetId      string            `json:"conditionSetId,omitempty"`      // Stored condition set to evaluate instead of inline conditions
+	ConditionSetVersion uint32            `json:"conditionSetVersion,omitempty"` // Pinned condition set version; the response reports the version evaluated
+	Parameters          Parameters        `json:"parameters,omitempty"`          // Values bound to condition placeholders
+	Mode                string            `json:"mode,omitempty"`                // all (default), any, atLeast:N or score
+	Threshold           *int              `json:"threshold,omitempty"`           // Score required to pass in score mode
+	Passed              bool              `json:"passed"`
+	PassedCount         int               `json:"passedCount"`     // Number of top-level conditions that passed
+	TotalCount          int               `json:"totalCount"`      // Number of top-level conditions evaluated
+	Score               *int              `json:"score,omitempty"` // Summed weight of passing conditions, reported in score mode
+	Results             []ConditionResult `json:"results,omitempty"`
 }
 
 // GetName returns the resource name
@@ -159,13 +244,39 @@ func (r *RestModel) SetReferencedStructs(references map[string]map[string]jsonap
 	return nil
 }
 
-// Transform converts a domain model to a REST model
+// Transform converts a domain model to a REST model. Explanations are only included for explained results.
 func Transform(result ValidationResult) (RestModel, error) {
-	return RestModel{
-		Id:      result.CharacterId(),
-		Passed:  result.Passed(),
-		Results: result.Results(),
-	}, nil
+	results := result.Results()
+	if !result.Explained() {
+		results = withoutExplanations(results)
+	}
+	rm := RestModel{
+		Id:          result.CharacterId(),
+		Mode:        result.Mode().String(),
+		Passed:      result.Passed(),
+		PassedCount: result.PassedCount(),
+		TotalCount:  result.TotalCount(),
+		Results:     results,
+	}
+	if result.Mode().Type() == ScoreMode {
+		score := result.Score()
+		threshold := result.Mode().Threshold()
+		rm.Score = &score
+		rm.Threshold = &threshold
+	}
+	return rm, nil
+}
+
+// ExtractMode parses the validation mode of a request, checking it can be satisfied by the request conditions
+func ExtractMode(rm RestModel) (Mode, error) {
+	mode, err := ParseMode(rm.Mode, rm.Threshold)
+	if err != nil {
+		return Mode{}, err
+	}
+	if err := mode.validateFor(len(rm.Conditions)); err != nil {
+		return Mode{}, err
+	}
+	return mode, nil
 }
 
 // Extract converts a REST model to domain model parameters for structured validation
@@ -175,98 +286,427 @@ func Extract(rm RestModel) (uint32, []ConditionInput, error) {
 		return 0, nil, fmt.Errorf("Id is required")
 	}
 
+	// Bind request parameters to the condition placeholders
+	conditions := make([]ConditionInput, 0, len(rm.Conditions))
+	for i, condition := range rm.Conditions {
+		bound, err := BindParameters(rm.Parameters)(condition)
+		if err != nil {
+			return 0, nil, ConditionError{Index: i, Err: err}
+		}
+		conditions = append(conditions, bound)
+	}
+
+	// Validate the condition list
+	if err := ValidateConditions(conditions); err != nil {
+		return 0, nil, err
+	}
+
+	return rm.Id, conditions, nil
+}
+
+// ConditionError reports a problem with the top-level condition at an index of the request
+type ConditionError struct {
+	Index int
+	Err   error
+}
+
+func (e ConditionError) Error() string {
+	return fmt.Sprintf("condition %d: %v", e.Index, e.Err)
+}
+
+func (e ConditionError) Unwrap() error {
+	return e.Err
+}
+
+// ValidateConditions validates a list of condition inputs, requiring at least one condition.
+// Placeholders are accepted unbound, with checks on the fields they supply deferred until binding.
+func ValidateConditions(conditions []ConditionInput) error {
 	// Validate that at least one condition is provided
-	if len(rm.Conditions) == 0 {
-		return 0, nil, fmt.Errorf("at least one condition is required")
+	if len(conditions) == 0 {
+		return fmt.Errorf("at least one condition is required")
 	}
 
 	// Validate each condition input
-	for i, condition := range rm.Conditions {
+	for i, condition := range conditions {
 		if err := validateConditionInput(condition); err != nil {
-			return 0, nil, fmt.Errorf("condition %d: %w", i, err)
+			return ConditionError{Index: i, Err: err}
 		}
 	}
+	return nil
+}
 
-	return rm.Id, rm.Conditions, nil
+// ResolveConditionSet replaces a condition set reference on the request with the conditions of the referenced version,
+// recording the version that was resolved
+func ResolveConditionSet(resolve ConditionSetResolver) func(rm RestModel) (RestModel, error) {
+	return func(rm RestModel) (RestModel, error) {
+		if rm.ConditionSetId == "" {
+			if rm.ConditionSetVersion != 0 {
+				return rm, fmt.Errorf("conditionSetVersion requires conditionSetId")
+			}
+			return rm, nil
+		}
+		if len(rm.Conditions) > 0 {
+			return rm, fmt.Errorf("conditions and conditionSetId are mutually exclusive")
+		}
+
+		id, err := uuid.Parse(rm.ConditionSetId)
+		if err != nil {
+			return rm, fmt.Errorf("invalid conditionSetId: %w", err)
+		}
+		conditions, version, err := resolve(id, rm.ConditionSetVersion)
+		if err != nil {
+			return rm, err
+		}
+		rm.Conditions = conditions
+		rm.ConditionSetVersion = version
+		return rm, nil
+	}
 }
 
 // validateConditionInput validates a single condition input
 func validateConditionInput(input ConditionInput) error {
+	if input.Weight != nil && *input.Weight < 1 {
+		return fmt.Errorf("weight must be positive")
+	}
+
+	// Expression nodes are validated in their parsed form
+	if input.Expression != "" {
+		parsed, err := input.resolveExpression()
+		if err != nil {
+			return err
+		}
+		return validateConditionInput(parsed)
+	}
+
+	// Group nodes are validated by validating each of their members
+	if input.IsGroup() {
+		group, members, err := input.groupMembers()
+		if err != nil {
+			return err
+		}
+		for i, member := range members {
+			if err := validateConditionInput(member); err != nil {
+				return fmt.Errorf("%s[%d]: %w", group, i, err)
+			}
+		}
+		return nil
+	}
+
+	return firstError(conditionProblems(input))
+}
+
+// conditionProblems returns every problem with a single, non-group condition input, in the order they are checked.
+// Checks that depend on a field that failed, such as operands of an unknown operator, are skipped.
+func conditionProblems(input ConditionInput) []error {
+	errs := make([]error, 0)
+
 	// Validate condition type
+	var definition *ConditionTypeDefinition
 	if input.Type == "" {
-		return fmt.Errorf("condition type is required")
+		errs = append(errs, fmt.Errorf("condition type is required"))
+	} else if d, err := LookupConditionType(input.Type); err != nil {
+		errs = append(errs, err)
+	} else {
+		definition = &d
 	}
 
 	// Validate operator
+	var operator Operator
+	operatorValid := false
 	if input.Operator == "" {
-		return fmt.Errorf("operator is required")
+		errs = append(errs, fmt.Errorf("operator is required"))
+	} else if o, err := ParseOperator(input.Operator); err != nil {
+		errs = append(errs, err)
+	} else {
+		operator, operatorValid = o, true
 	}
 
-	// Validate supported operators
-	switch input.Operator {
-	case "=", ">", "<", ">=", "<=":
-		// Valid operators
-	default:
-		return fmt.Errorf("unsupported operator: %s", input.Operator)
+	// Validate supported operators and their operands
+	hasStep := input.Step != "" || input.StepParam != ""
+	operandsValid := false
+	if definition != nil && operatorValid {
+		if err := validateOperands(*definition, operator, input.Values, input.Min, input.Max, hasStep); err != nil {
+			errs = append(errs, err)
+		} else {
+			operandsValid = true
+		}
 	}
 
-	// Validate condition-specific requirements
-	switch input.Type {
-	case "item":
-		// Item conditions require referenceId (or legacy itemId)
-		if input.ReferenceId == 0 && input.ItemId == 0 {
-			return fmt.Errorf("referenceId is required for item conditions")
-		}
-		if input.ItemId != 0 && input.ReferenceId != 0 {
-			return fmt.Errorf("both itemId and referenceId specified - use referenceId only")
-		}
-	case "questStatus":
-		// Quest status conditions require referenceId
-		if input.ReferenceId == 0 {
-			return fmt.Errorf("referenceId is required for quest status conditions")
-		}
-		// Quest status values should be valid enum values (0-3)
-		if input.Value < 0 || input.Value > 3 {
-			return fmt.Errorf("quest status value must be between 0 and 3 (UNDEFINED=0, NOT_STARTED=1, STARTED=2, COMPLETED=3)")
-		}
-	case "questProgress":
-		// Quest progress conditions require referenceId and step
-		if input.ReferenceId == 0 {
-			return fmt.Errorf("referenceId is required for quest progress conditions")
-		}
-		if input.Step == "" {
-			return fmt.Errorf("step is required for quest progress conditions")
-		}
-	case "guildId":
-		// Guild ID conditions require a valid guild ID value
-		if input.Value <= 0 {
-			return fmt.Errorf("guild ID value must be greater than 0")
-		}
-	case "guildRank":
-		// Guild rank conditions should have reasonable rank values
-		if input.Value < 0 || input.Value > 5 {
-			return fmt.Errorf("guild rank value must be between 0 and 5")
-		}
-	case "hasUnclaimedMarriageGifts":
-		// Marriage gift conditions should be boolean (0 or 1)
-		if input.Value != 0 && input.Value != 1 {
-			return fmt.Errorf("marriage gift value must be 0 or 1")
-		}
-		// Only equals operator makes sense for boolean conditions
-		if input.Operator != "=" {
-			return fmt.Errorf("marriage gift conditions only support '=' operator")
+	// Validate placeholders
+	if err := input.validatePlaceholders(); err != nil {
+		errs = append(errs, err)
+	}
+	if operatorValid && input.ValueParam != "" && (operator.IsSet() || operator == Between) {
+		errs = append(errs, fmt.Errorf("valueParam is not supported by the %s operator", operator))
+	}
+
+	// Item IDs are a deprecated spelling of referenceId
+	if input.ItemId != 0 && input.ReferenceId != 0 {
+		errs = append(errs, fmt.Errorf("both itemId and referenceId specified - use referenceId only"))
+	}
+	referenceId := input.ReferenceId
+	if referenceId == 0 {
+		referenceId = input.ItemId
+	}
+
+	if definition == nil {
+		return errs
+	}
+
+	// Value checks apply to every operand the condition compares against. An unbound value is checked once bound.
+	if operandsValid {
+		expected := []int{input.Value}
+		if operator.IsSet() {
+			expected = input.Values
+		} else if operator == Between {
+			expected = []int{*input.Min, *input.Max}
+		} else if input.ValueParam != "" {
+			expected = nil
 		}
-	case "level", "reborns", "dojoPoints", "vanquisherKills", "gmLevel":
-		// Numeric conditions should have non-negative values
-		if input.Value < 0 {
-			return fmt.Errorf("%s value must be non-negative", input.Type)
+		hasReference := input.ReferenceId != 0 || input.ItemId != 0 || input.ReferenceIdParam != ""
+
+		// Validate the operator, expected values and required fields against the condition type definition
+		errs = append(errs, definition.problems(operator, expected, hasReference, hasStep)...)
+	}
+	errs = append(errs, definition.qualifierProblems(referenceId, input.Step)...)
+
+	return errs
+}
+
+// AccountRestModel represents the REST model for account validation requests and responses. The account and world are
+// identified by the request path, and the conditions are evaluated against every character the account has in the world.
+//
+// Example request passing when any character on the account has reached level 120:
+//   {
+//     "scope": "any",
+//     "conditions": [
+//       { "type": "level", "operator": ">=", "value": 120 }
+//     ]
+//   }
+//
+// Example response:
+//   {
+//     "scope": "any",
+//     "passed": true,
+//     "passedCount": 1,
+//     "totalCount": 2,
+//     "characters": [
+//       { "characterId": 1001, "mode": "all", "passed": true, "passedCount": 1, "totalCount": 1, "results": [...] },
+//       { "characterId": 1002, "mode": "all", "passed": false, "passedCount": 0, "totalCount": 1, "results": [...] }
+//     ]
+//   }
+type AccountRestModel struct {
+	Id                  uint32                      `json:"-"`
+	Conditions          []ConditionInput            `json:"conditions,omitempty"`
+	ConditionSetId      string                      `json:"conditionSetId,omitempty"`      // Stored condition set to evaluate instead of inline conditions
+	ConditionSetVersion uint32                      `json:"conditionSetVersion,omitempty"` // Pinned condition set version; the response reports the version evaluated
+	Parameters          Parameters                  `json:"parameters,omitempty"`          // Values bound to condition placeholders
+	Mode                string                      `json:"mode,omitempty"`                // Mode combining the condition results of each character
+	Threshold           *int                        `json:"threshold,omitempty"`           // Score required to pass in score mode
+	Scope               string                      `json:"scope,omitempty"`               // all (default), any or count:N characters
+	Passed              bool                        `json:"passed"`
+	PassedCount         int                         `json:"passedCount"` // Number of characters that passed
+	TotalCount          int                         `json:"totalCount"`  // Number of characters on the account
+	Characters          []AccountCharacterRestModel `json:"characters,omitempty"`
+}
+
+// AccountCharacterRestModel represents the validation of a character on an account
+type AccountCharacterRestModel struct {
+	CharacterId uint32 `json:"characterId"`
+	RestModel
+}
+
+// GetName returns the resource name
+func (r AccountRestModel) GetName() string {
+	return AccountResource
+}
+
+// GetID returns the resource ID, which is the account ID
+func (r AccountRestModel) GetID() string {
+	return strconv.FormatUint(uint64(r.Id), 10)
+}
+
+// SetID sets the resource ID. Account validation requests identify the account by path, so an empty ID is accepted.
+func (r *AccountRestModel) SetID(idStr string) error {
+	if idStr == "" {
+		return nil
+	}
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid account ID: %w", err)
+	}
+	r.Id = uint32(id)
+	return nil
+}
+
+// validation returns the validation request evaluated against each character on the account
+func (r AccountRestModel) validation() RestModel {
+	return RestModel{
+		Id:                  r.Id,
+		Conditions:          r.Conditions,
+		ConditionSetId:      r.ConditionSetId,
+		ConditionSetVersion: r.ConditionSetVersion,
+		Parameters:          r.Parameters,
+		Mode:                r.Mode,
+		Threshold:           r.Threshold,
+	}
+}
+
+// TransformAccount converts an account validation result to a REST model
+func TransformAccount(result AccountValidationResult) (AccountRestModel, error) {
+	characters := make([]AccountCharacterRestModel, 0, len(result.Characters()))
+	for _, c := range result.Characters() {
+		rm, err := Transform(c)
+		if err != nil {
+			return AccountRestModel{}, err
 		}
-	case "jobId", "meso", "mapId", "fame", "gender", "strength", "dexterity", "intelligence", "luck":
-		// Standard numeric conditions - basic validation
-		break
-	default:
-		return fmt.Errorf("unsupported condition type: %s", input.Type)
+		characters = append(characters, AccountCharacterRestModel{CharacterId: c.CharacterId(), RestModel: rm})
 	}
+	return AccountRestModel{
+		Id:          result.AccountId(),
+		Scope:       result.Scope().String(),
+		Passed:      result.Passed(),
+		PassedCount: result.PassedCount(),
+		TotalCount:  result.TotalCount(),
+		Characters:  characters,
+	}, nil
+}
+
+// CompileRestModel represents the REST model for compile requests and responses
+//
+// Example request:
+//   {
+//     "conditions": [
+//       { "expression": "level>=30 && item[2000001]>=$count" },
+//       { "type": "guildRank", "operator": "<=", "value": 9 }
+//     ],
+//     "parameters": { "count": 10 }
+//   }
+//
+// Example response:
+//   {
+//     "valid": false,
+//     "conditions": [
+//       {
+//         "all": [
+//           { "type": "level", "operator": ">=", "value": 30 },
+//           { "type": "item", "operator": ">=", "value": 10, "referenceId": 2000001 }
+//         ]
+//       }
+//     ],
+//     "errors": [
+//       { "index": 1, "path": "conditions[1]", "message": "guild rank value must be between 0 and 5" }
+//     ],
+//     "dataSources": ["CHARACTERS", "INVENTORY"]
+//   }
+type CompileRestModel struct {
+	Id          string           `json:"-"`
+	Conditions  []ConditionInput `json:"conditions"`            // Conditions to compile; normalized in the response
+	Parameters  Parameters       `json:"parameters,omitempty"`  // Values bound to condition placeholders
+	Mode        string           `json:"mode,omitempty"`        // Validation mode to check against the conditions
+	Threshold   *int             `json:"threshold,omitempty"`   // Score required to pass in score mode
+	Valid       bool             `json:"valid"`                 // Whether every condition compiled
+	Errors      []CompileError   `json:"errors"`                // Every error found, with the index and path of its condition
+	DataSources []DataSource     `json:"dataSources"`           // Upstream resources evaluating the conditions would fetch
+}
+
+// GetName returns the resource name
+func (r CompileRestModel) GetName() string {
+	return CompileResource
+}
+
+// GetID returns the resource ID
+func (r CompileRestModel) GetID() string {
+	return r.Id
+}
 
+// SetID sets the resource ID. Compile requests do not require an ID.
+func (r *CompileRestModel) SetID(idStr string) error {
+	r.Id = idStr
 	return nil
 }
+
+// TransformCompilation converts a compilation to a REST model
+func TransformCompilation(c Compilation) (CompileRestModel, error) {
+	conditions := make([]ConditionInput, 0, len(c.Conditions()))
+	for _, condition := range c.Conditions() {
+		conditions = append(conditions, condition.Input())
+	}
+	return CompileRestModel{
+		Conditions:  conditions,
+		Valid:       c.Valid(),
+		Errors:      c.Errors(),
+		DataSources: c.DataSources(),
+	}, nil
+}
+
+// ConditionTypeRestModel represents the REST model describing a supported condition type
+//
+// Example response entry:
+//   {
+//     "type": "condition-types",
+//     "id": "questProgress",
+//     "attributes": {
+//       "name": "quest progress",
+//       "operators": ["=", "!=", ">", "<", ">=", "<=", "in", "notIn", "between"],
+//       "requiresReferenceId": true,
+//       "optionalReferenceId": false,
+//       "requiresStep": true,
+//       "optionalStep": false,
+//       "dataSource": "QUESTS",
+//       "dataSources": ["QUESTS"],
+//       "field": "quests[{referenceId}].progress[{step}]"
+//     }
+//   }
+type ConditionTypeRestModel struct {
+	Id                  string       `json:"-"`
+	Name                string       `json:"name"`
+	Operators           []Operator   `json:"operators"`
+	MinValue            *int         `json:"minValue,omitempty"`
+	MaxValue            *int         `json:"maxValue,omitempty"`
+	RequiresReferenceId bool         `json:"requiresReferenceId"`
+	OptionalReferenceId bool         `json:"optionalReferenceId"` // A referenceId narrows the condition but may be omitted
+	RequiresStep        bool         `json:"requiresStep"`
+	OptionalStep        bool         `json:"optionalStep"` // A step narrows the condition but may be omitted
+	DataSource          DataSource   `json:"dataSource"`   // Resource holding the field
+	DataSources         []DataSource `json:"dataSources"`  // Every resource evaluating the condition reads
+	Field               string       `json:"field"`
+	StepValues          string       `json:"stepValues,omitempty"` // Configuration field of the list a step may name in place of values
+	Wraps               bool         `json:"wraps"`                // Between ranges may wrap, with min greater than max
+}
+
+// GetName returns the resource name
+func (r ConditionTypeRestModel) GetName() string {
+	return ConditionTypeResource
+}
+
+// GetID returns the resource ID, which is the condition type
+func (r ConditionTypeRestModel) GetID() string {
+	return r.Id
+}
+
+// SetID sets the resource ID
+func (r *ConditionTypeRestModel) SetID(idStr string) error {
+	r.Id = idStr
+	return nil
+}
+
+// TransformConditionType converts a condition type definition to a REST model
+func TransformConditionType(d ConditionTypeDefinition) (ConditionTypeRestModel, error) {
+	return ConditionTypeRestModel{
+		Id:                  string(d.Type()),
+		Name:                d.Name(),
+		Operators:           d.Operators(),
+		MinValue:            d.MinValue(),
+		MaxValue:            d.MaxValue(),
+		RequiresReferenceId: d.RequiresReferenceId(),
+		OptionalReferenceId: d.OptionalReferenceId(),
+		RequiresStep:        d.RequiresStep(),
+		OptionalStep:        d.OptionalStep(),
+		DataSource:          d.Source(),
+		DataSources:         d.DataSources(),
+		Field:               d.Field(),
+		StepValues:          d.StepValues(),
+		Wraps:               d.Wraps(),
+	}, nil
+}
@@ -0,0 +1,37 @@
+package validation
+
+import (
+	"atlas-query-aggregator/character"
+	"atlas-query-aggregator/equipment"
+	"fmt"
+
+	"github.com/Chronicle20/atlas-constants/inventory/slot"
+)
+
+// StatBreakdown splits the actual value of a total statistic condition into the base value of the character and the
+// bonus granted by equipped items
+type StatBreakdown struct {
+	Base      int               `json:"base"`
+	Equipment int               `json:"equipment"`
+	Slots     map[slot.Type]int `json:"slots,omitempty"` // Equipment bonus by slot type, regular and cash combined
+}
+
+// totalStatistic creates an evaluator comparing the base value of a statistic plus the bonus of every equipped item.
+// Statistics the character model carries no base value for have a base of 0.
+func totalStatistic(label string, statistic equipment.Statistic, base func(character.Model) int) Evaluator {
+	return NewEvaluator(func(c Condition, ctx ValidationContext) Evaluation {
+		m := ctx.Character()
+		breakdown := &StatBreakdown{Slots: m.Equipment().Bonuses(statistic)}
+		if base != nil {
+			breakdown.Base = base(m)
+		}
+		for _, bonus := range breakdown.Slots {
+			breakdown.Equipment += bonus
+		}
+		return Evaluation{
+			ActualValue: breakdown.Base + breakdown.Equipment,
+			Description: fmt.Sprintf("Total %s %s", label, c.Expectation()),
+			Breakdown:   breakdown,
+		}
+	}, CharactersSource, InventorySource)
+}
@@ -0,0 +1,104 @@
+package validation
+
+import (
+	"atlas-query-aggregator/asset"
+	"atlas-query-aggregator/character"
+	"atlas-query-aggregator/compartment"
+	"atlas-query-aggregator/inventory"
+	"reflect"
+	"testing"
+
+	inventory_type "github.com/Chronicle20/atlas-constants/inventory"
+	"github.com/Chronicle20/atlas-constants/inventory/slot"
+	"github.com/google/uuid"
+)
+
+// createTestStatisticEquipment creates a character with base strength 40 and max HP 500, wearing a hat granting 5
+// strength and 100 HP, a cash hat granting 2 strength and a weapon granting 3 strength and 50 weapon attack
+func createTestStatisticEquipment() character.Model {
+	compartmentId := uuid.New()
+
+	hat := asset.NewEquipableReferenceDataBuilder()
+	hat.SetStrength(5)
+	hat.SetHp(100)
+	weapon := asset.NewEquipableReferenceDataBuilder()
+	weapon.SetStrength(3)
+	weapon.SetWeaponAttack(50)
+	cashHat := asset.NewCashEquipableReferenceDataBuilder()
+	cashHat.SetStrength(2)
+
+	equip := compartment.NewBuilder(compartmentId, 1, inventory_type.TypeValueEquip, 24).
+		AddAsset(asset.NewBuilder[any](1, compartmentId, 1002140, 1, asset.ReferenceTypeEquipable).SetSlot(-1).SetReferenceData(hat.Build()).Build()).
+		AddAsset(asset.NewBuilder[any](2, compartmentId, 1302000, 2, asset.ReferenceTypeEquipable).SetSlot(-11).SetReferenceData(weapon.Build()).Build()).
+		AddAsset(asset.NewBuilder[any](3, compartmentId, 1002186, 3, asset.ReferenceTypeCashEquipable).SetSlot(-101).SetReferenceData(cashHat.Build()).Build()).
+		Build()
+
+	return character.NewModelBuilder().SetId(1).SetStrength(40).SetMaxHp(500).Build().
+		SetInventory(inventory.NewBuilder(1).SetEquipable(equip).Build())
+}
+
+// TestCondition_Evaluate_TotalStatistics tests total statistic conditions and their base and equipment breakdown
+func TestCondition_Evaluate_TotalStatistics(t *testing.T) {
+	char := createTestStatisticEquipment()
+
+	tests := []struct {
+		name            string
+		expression      string
+		wantPassed      bool
+		wantDescription string
+		wantBreakdown   StatBreakdown
+	}{
+		{
+			name:            "Base and regular and cash equipment",
+			expression:      "totalStrength>=50",
+			wantPassed:      true,
+			wantDescription: "Total Strength >= 50",
+			wantBreakdown:   StatBreakdown{Base: 40, Equipment: 10, Slots: map[slot.Type]int{slot.TypeHat: 7, slot.TypeWeapon: 3}},
+		},
+		{
+			name:            "Base read from max HP",
+			expression:      "totalHp between [550, 650]",
+			wantPassed:      true,
+			wantDescription: "Total HP between 550 and 650",
+			wantBreakdown:   StatBreakdown{Base: 500, Equipment: 100, Slots: map[slot.Type]int{slot.TypeHat: 100}},
+		},
+		{
+			name:            "Equipment only statistic",
+			expression:      "totalWeaponAttack>50",
+			wantPassed:      false,
+			wantDescription: "Total Weapon Attack > 50",
+			wantBreakdown:   StatBreakdown{Base: 0, Equipment: 50, Slots: map[slot.Type]int{slot.TypeWeapon: 50}},
+		},
+		{
+			name:            "No equipment bonus",
+			expression:      "totalJump=0",
+			wantPassed:      true,
+			wantDescription: "Total Jump = 0",
+			wantBreakdown:   StatBreakdown{Slots: map[slot.Type]int{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, err := NewConditionBuilder().FromExpression(tt.expression).Build()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			result := condition.Evaluate(char)
+			if result.Passed != tt.wantPassed || result.Description != tt.wantDescription {
+				t.Errorf("Evaluate() = %+v, want passed %v, description %q", result, tt.wantPassed, tt.wantDescription)
+			}
+			if result.Breakdown == nil || !reflect.DeepEqual(*result.Breakdown, tt.wantBreakdown) {
+				t.Fatalf("Breakdown = %+v, want %+v", result.Breakdown, tt.wantBreakdown)
+			}
+			if result.ActualValue != tt.wantBreakdown.Base+tt.wantBreakdown.Equipment {
+				t.Errorf("ActualValue = %d, want base plus equipment", result.ActualValue)
+			}
+		})
+	}
+
+	base := Condition{conditionType: StrengthCondition, operator: GreaterEqual, value: 50}.Evaluate(char)
+	if base.Passed || base.ActualValue != 40 || base.Breakdown != nil {
+		t.Errorf("Expected strength to read the base value only, got %+v", base)
+	}
+}